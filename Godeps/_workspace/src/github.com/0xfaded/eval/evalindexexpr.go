@@ -21,7 +21,7 @@ func evalIndexExpr(index *IndexExpr, env Env) ([]reflect.Value, error) {
 		v := x.MapIndex(k[0])
 		ok := v.IsValid()
 		if !ok {
-			v = reflect.New(t.Key()).Elem()
+			v = reflect.New(t.Elem()).Elem()
 		}
 		return []reflect.Value{v, reflect.ValueOf(ok)}, nil
 	case reflect.Ptr:
@@ -217,6 +217,23 @@ func listNewIdentsFromAssign(assign *ast.AssignStmt) (idents []*ast.Ident) {
 	return
 }
 
+// assignsExisting reports whether node is an assignment or an increment that
+// may change a variable that existed before it. Short variable declarations
+// of only new variables and assignments to only the blank identifier cannot.
+func assignsExisting(node ast.Node) bool {
+	switch i := node.(type) {
+	case *ast.IncDecStmt:
+		return true
+	case *ast.AssignStmt:
+		for _, expr := range i.Lhs {
+			if ident, ok := expr.(*ast.Ident); !ok || ident.Name != "_" && !isNewIdent(ident) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func IsBreakpoint(node ast.Node) (b bool) {
 	return isOldBreakpoint(node) || isNewBreakpoint(node)
 }
@@ -329,6 +346,38 @@ func inputsOrOutputs(fieldList *ast.FieldList, prefix string) (decl []ast.Stmt,
 	return decl, all
 }
 
+// nameOutputs names the unnamed and blank results in fieldList, so that the
+// deferred call to ExitFunc can see the values the function returns. It
+// returns all of the results.
+func nameOutputs(fieldList *ast.FieldList, prefix string) (all []ast.Expr) {
+	if fieldList == nil {
+		return
+	}
+	count := 1
+	for _, field := range fieldList.List {
+		if field.Names == nil {
+			field.Names = []*ast.Ident{blank}
+		}
+		for i, name := range field.Names {
+			if name.Name == "_" {
+				field.Names[i] = ast.NewIdent(prefix + strconv.Itoa(count))
+			}
+			count++
+			all = append(all, field.Names[i])
+		}
+	}
+	return all
+}
+
+// addresses returns &x for each x in exprs.
+func addresses(exprs []ast.Expr) []ast.Expr {
+	addrs := make([]ast.Expr, len(exprs))
+	for i, x := range exprs {
+		addrs[i] = &ast.UnaryExpr{Op: token.AND, X: x}
+	}
+	return addrs
+}
+
 func genEnterFunc(fn *ast.FuncDecl, inputs, outputs []ast.Expr) (stmts []ast.Stmt) {
 	var (
 		pseudoIdent ast.Expr = fn.Name
@@ -394,11 +443,11 @@ func genEnterFuncLit(fnType *ast.FuncType, body *ast.BlockStmt, hasRecovers bool
 					}()
 				}
 				if ctx, ok := godebug.EnterFuncLit(%s); ok {
-					defer godebug.ExitFunc(ctx)
+					defer godebug.ExitFunc(ctx, %s)
 					%s(ctx)
 				}
 				return %s
-			`, deferCloseQuit, decl, fn, outputs, fnType.Results, body.List, fn, fn, outputs)
+			`, deferCloseQuit, decl, fn, outputs, fnType.Results, body.List, fn, addresses(outputs), fn, outputs)
 	} else {
 		newBody.List = astPrintf(`
 				{{%s}}
@@ -472,9 +521,8 @@ func (v *visitor) finalizeNode() {
 			rewriteFnWithRecovers(i.Body, i.Type)
 			break
 		}
-		declOuts, outputs := inputsOrOutputs(i.Type.Results, idents.result)
-		declIns, inputs := inputsOrOutputs(i.Type.Params, idents.input)
-		prepend := append(declIns, declOuts...)
+		outputs := nameOutputs(i.Type.Results, idents.result)
+		prepend, inputs := inputsOrOutputs(i.Type.Params, idents.input)
 		// We will refer to this function by name when we call genEnterFunc. If any of the
 		// parameters have the same name as the function, they will conflict. To get around that,
 		// rename any such parameters now.
@@ -482,7 +530,7 @@ func (v *visitor) finalizeNode() {
 		prepend = append(prepend, genEnterFunc(i, inputs, outputs)...)
 		if !(pkg.Name() == "main" && i.Name.Name == "main") {
 			prepend = append(prepend, &ast.DeferStmt{
-				Call: newCall(idents.godebug, "ExitFunc", ast.NewIdent(idents.ctx)),
+				Call: newCall(idents.godebug, "ExitFunc", append([]ast.Expr{ast.NewIdent(idents.ctx)}, addresses(outputs)...)...),
 			})
 		}
 
@@ -563,7 +611,8 @@ func (v *visitor) finalizeNode() {
 		}
 		newDecls = append(newDecls, varDecl(&ast.ValueSpec{
 			Names:  []*ast.Ident{ast.NewIdent(idents.fileScope)},
-			Values: []ast.Expr{newCall(idents.godebug, "EnteringNewFile", ast.NewIdent(idents.pkgScope), ast.NewIdent(idents.fileContents))},
+			Values: []ast.Expr{newCall(idents.godebug, "EnteringNewFileAt", ast.NewIdent(idents.pkgScope),
+				newStringLit(strconv.Quote(filepath.ToSlash(fs.Position(i.Pos()).Filename))), ast.NewIdent(idents.fileContents))},
 		}))
 		i.Decls = append(newDecls, i.Decls...)
 	}
@@ -747,6 +796,11 @@ func (v *visitor) Visit(node ast.Node) ast.Visitor {
 		v.stmtBuf = append(v.stmtBuf, newDeclareCall("", newIdents))
 	}
 
+	// If this statement may have changed a variable that existed before it, output an Assigned call, which checks the watches.
+	if assignsExisting(node) {
+		v.stmtBuf = append(v.stmtBuf, newCallStmt(idents.godebug, "Assigned", ast.NewIdent(idents.ctx), ast.NewIdent(v.scopeVar), newInt(pos2line(node.Pos()))))
+	}
+
 	// If this statement declared new constants, output a Constant call.
 	if decl, ok := node.(*ast.DeclStmt); ok {
 		if gen := decl.Decl.(*ast.GenDecl); gen.Tok == token.CONST {
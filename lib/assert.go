@@ -0,0 +1,55 @@
+package godebug
+
+// This file implements the "assert" command and SetAssertHandler.
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// An AssertHandler is called when an assert command fails, with the
+// expression that was asserted and the pause the command ran at. If the
+// expression could not be evaluated, err says why.
+type AssertHandler func(expr string, err error, s Snapshot)
+
+var assertHandler AssertHandler
+
+// SetAssertHandler installs h to be called when an assert command fails, in
+// place of exiting the program. Passing nil removes the handler.
+//
+// Without a handler, a failed assert only prints the failure when commands are
+// typed at a terminal. When commands come from a PauseHandler, from SetInput or
+// from a file or pipe on standard input, it also exits the program with status
+// 1, so that a scripted session can serve as a test.
+func SetAssertHandler(h AssertHandler) {
+	assertHandler = h
+}
+
+func assertCommand(s *Scope, line int, expr string) {
+	if expr == "" {
+		fmt.Fprintln(output, "usage: assert <expression>")
+		return
+	}
+	v, err := evalOne(s, expr)
+	if err == nil {
+		if v.Kind() != reflect.Bool {
+			err = fmt.Errorf("%s has type %s, not bool", expr, v.Type())
+		} else if v.Bool() {
+			return
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(output, "*** assertion failed at line %d: %s: %v\n", line, expr, err)
+	} else {
+		fmt.Fprintf(output, "*** assertion failed at line %d: %s\n", line, expr)
+	}
+	if h := assertHandler; h != nil {
+		h(expr, err, newSnapshot(s, line))
+		return
+	}
+	if pauseHandler != nil || promptOnOutput {
+		fmt.Fprintln(output, "Exiting with status 1.")
+		os.Exit(1)
+	}
+}
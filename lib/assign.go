@@ -0,0 +1,168 @@
+package godebug
+
+// This file implements "set <variable> = <expression>", which changes the
+// value of a variable of the paused program.
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/mailgun/godebug/Godeps/_workspace/src/github.com/0xfaded/eval"
+)
+
+const assignUsage = "usage: set var <variable> = <expression>"
+
+// isAssignment reports whether the arguments of "set" assign to a variable
+// rather than change a setting: they contain "=", and do not start with the
+// name of a setting.
+func isAssignment(args string) bool {
+	i := strings.Index(args, "=")
+	if i < 0 {
+		return false
+	}
+	words := strings.Fields(args[:i])
+	if len(words) == 0 {
+		return true
+	}
+	_, ok := settings[words[0]]
+	return !ok
+}
+
+// assignCommand implements "set [var] <variable> = <expression>". The
+// variable may be a field of a struct variable, like p.x, but not an element
+// of a slice or map. The expression is evaluated like print, and may be of a
+// different numeric type, as long as its value fits the variable exactly.
+func assignCommand(s *Scope, args string) {
+	i := strings.Index(args, "=")
+	if i < 0 {
+		fmt.Fprintln(output, assignUsage)
+		return
+	}
+	name, expr := strings.TrimSpace(args[:i]), strings.TrimSpace(args[i+1:])
+	if name == "" || expr == "" {
+		fmt.Fprintln(output, assignUsage)
+		return
+	}
+	dst, ok := resolveSelector(s, name)
+	if !ok {
+		fmt.Fprintf(output, "%s is not a variable in scope\n", name)
+		return
+	}
+	if dst, ok = accessible(dst); !ok || !dst.CanSet() {
+		fmt.Fprintf(output, "godebug cannot set %s\n", name)
+		return
+	}
+	v, err := evalOne(s, expr)
+	if err != nil {
+		fmt.Fprintln(output, err)
+		return
+	}
+	if v, err = assignable(v, dst.Type()); err != nil {
+		fmt.Fprintf(output, "cannot set %s: %v\n", name, err)
+		return
+	}
+	dst.Set(v)
+	fmt.Fprintf(output, "%s = %s\n", name, valueString(name, dst))
+}
+
+// assignable returns v as a value of type t, or why it cannot be one. The
+// evaluator leaves constants untyped, so they are converted as the compiler
+// would. Typed numbers are converted too if the conversion loses nothing,
+// since the constant expression "n + 1" has n's type however it is used.
+func assignable(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	switch x := v.Interface().(type) {
+	case eval.UntypedNil:
+		switch t.Kind() {
+		case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+			return reflect.Zero(t), nil
+		}
+		return v, fmt.Errorf("cannot use nil as %s", t)
+	case *eval.ConstNumber:
+		return constNumber(x, t)
+	}
+	if v.Type().AssignableTo(t) {
+		return v, nil
+	}
+	if isNumber(v.Kind()) && isNumber(t.Kind()) {
+		c := v.Convert(t)
+		if c.Convert(v.Type()).Interface() == v.Interface() && !(isNegative(v) && c.Kind() >= reflect.Uint && c.Kind() <= reflect.Uintptr) {
+			return c, nil
+		}
+		return v, fmt.Errorf("%s does not fit in %s", valueString("", v), t)
+	}
+	return v, fmt.Errorf("cannot use %s value as %s", v.Type(), t)
+}
+
+// constNumber returns the untyped constant c as a value of type t. As in Go,
+// an interface gets the constant's default type, like int for 5.
+func constNumber(c *eval.ConstNumber, t reflect.Type) (reflect.Value, error) {
+	if t.Kind() == reflect.Interface {
+		d := c.Type.DefaultPromotion()
+		if !d.Implements(t) {
+			return reflect.Value{}, fmt.Errorf("cannot use %s value as %s", d, t)
+		}
+		v, err := constNumber(c, d)
+		if err != nil {
+			return v, err
+		}
+		return v.Convert(t), nil
+	}
+	var (
+		v          interface{}
+		truncated  bool
+		overflowed bool
+	)
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, truncated, overflowed = c.Value.Int(t.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v, truncated, overflowed = c.Value.Uint(t.Bits())
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, truncated, _ = c.Value.Float64()
+		v, overflowed = f, overflowsFloat(f, t.Bits())
+	case reflect.Complex64, reflect.Complex128:
+		var z complex128
+		z, _ = c.Value.Complex128()
+		v, overflowed = z, overflowsFloat(real(z), t.Bits()/2) || overflowsFloat(imag(z), t.Bits()/2)
+	default:
+		return reflect.Value{}, fmt.Errorf("cannot use %s (untyped number) as %s", c, t)
+	}
+	if truncated || overflowed {
+		return reflect.Value{}, fmt.Errorf("%s does not fit in %s", c, t)
+	}
+	return reflect.ValueOf(v).Convert(t), nil
+}
+
+// overflowsFloat reports whether f, rounded to a float of the given size, is
+// infinite, as it is for a constant too large for that size.
+func overflowsFloat(f float64, bits int) bool {
+	if bits == 32 {
+		return math.IsInf(float64(float32(f)), 0)
+	}
+	return math.IsInf(f, 0)
+}
+
+// isNumber reports whether k is an integer or floating-point kind.
+func isNumber(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isNegative reports whether v, a number, is below zero.
+func isNegative(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() < 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() < 0
+	}
+	return false
+}
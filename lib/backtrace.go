@@ -0,0 +1,137 @@
+package godebug
+
+// This file implements "backtrace", which prints the frames of the paused
+// goroutine, and "backtrace export", which writes them to a file for bug
+// reports.
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A backtraceFrame is a call to a generated function that has not returned.
+type backtraceFrame struct {
+	Function string `json:"function"`
+
+	// File and Line are where the function is, or empty if it has not
+	// reached a line yet.
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line"`
+	Source string `json:"source"`
+
+	// Locals maps names to formatted values. It is only set on request.
+	Locals map[string]string `json:"locals,omitempty"`
+}
+
+// backtrace returns the frames of c's goroutine, innermost first. Calls to code
+// that godebug did not generate are left out.
+func backtrace(c *Context, withLocals bool) []backtraceFrame {
+	var frames []backtraceFrame
+	for ; c != nil; c = c.parent {
+		f := backtraceFrame{Function: c.funcName()}
+		if c.scope != nil && c.line > 0 {
+			f.File = c.scope.file.name
+			f.Line = c.line
+			f.Source = c.scope.sourceLine(c.line)
+			if withLocals {
+				f.Locals = make(map[string]string)
+				for name, v := range c.scope.locals() {
+					f.Locals[name] = formatValue(redact(name, v))
+				}
+			}
+		}
+		frames = append(frames, f)
+	}
+	return frames
+}
+
+// printBacktrace implements "backtrace [count]", which prints the innermost
+// count frames, or all of them.
+func printBacktrace(c *Context, arg string) {
+	frames := backtrace(c, false)
+	if arg != "" {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 {
+			fmt.Fprintln(output, "usage: backtrace [count]")
+			return
+		}
+		if n < len(frames) {
+			frames = frames[:n]
+		}
+	}
+	for i, f := range frames {
+		fmt.Fprintln(output, frameLine(i, f))
+	}
+}
+
+// frameLine formats frame i for backtrace and frame.
+func frameLine(i int, f backtraceFrame) string {
+	if f.Line == 0 {
+		return fmt.Sprintf("#%d %s", i, f.Function)
+	}
+	return fmt.Sprintf("#%d %s, line %d of %s: %s", i, f.Function, f.Line, path.Base(f.File), f.Source)
+}
+
+// exportedBacktrace is the JSON form of "backtrace export/json".
+type exportedBacktrace struct {
+	Goroutine uint32           `json:"goroutine"`
+	Label     string           `json:"label,omitempty"`
+	Time      time.Time        `json:"time"`
+	Frames    []backtraceFrame `json:"frames"`
+}
+
+// exportBacktrace implements "backtrace export" and "backtrace export/json".
+func exportBacktrace(c *Context, args []string, asJSON bool) {
+	withLocals := len(args) == 2 && args[1] == "locals"
+	if len(args) != 1 && !withLocals {
+		fmt.Fprintln(output, "usage: backtrace export[/json] <file> [locals]")
+		return
+	}
+	bt := exportedBacktrace{Goroutine: c.goroutine, Label: goroutineLabel(c.goroutine), Time: time.Now(), Frames: backtrace(c, withLocals)}
+	var b []byte
+	if asJSON {
+		var err error
+		if b, err = json.MarshalIndent(bt, "", "\t"); err != nil {
+			fmt.Fprintln(output, err)
+			return
+		}
+		b = append(b, '\n')
+	} else {
+		b = []byte(bt.String())
+	}
+	if err := ioutil.WriteFile(args[0], b, 0666); err != nil {
+		fmt.Fprintln(output, err)
+		return
+	}
+	fmt.Fprintf(output, "Wrote %d frames to %s.\n", len(bt.Frames), args[0])
+}
+
+func (bt exportedBacktrace) String() string {
+	name := fmt.Sprint(bt.Goroutine)
+	if bt.Label != "" {
+		name += " (" + bt.Label + ")"
+	}
+	lines := []string{fmt.Sprintf("godebug backtrace of goroutine %s at %s", name, bt.Time.Format(time.RFC3339))}
+	for i, f := range bt.Frames {
+		if f.Line == 0 {
+			lines = append(lines, fmt.Sprintf("#%d %s", i, f.Function))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("#%d %s, line %d: %s", i, f.Function, f.Line, f.Source))
+		names := make([]string, 0, len(f.Locals))
+		for name := range f.Locals {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			lines = append(lines, fmt.Sprintf("    %s = %s", name, f.Locals[name]))
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,818 @@
+package godebug
+
+// This file implements the "break" commands.
+
+import (
+	"fmt"
+	"go/parser"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// returnBreaks holds the names of the functions given to "break return".
+// Running goroutines consult it, so it is guarded by a mutex. returnBreakCount
+// lets them skip the lock when there are no such breakpoints.
+var (
+	returnBreaksMu   sync.Mutex
+	returnBreaks     = make(map[string]bool)
+	returnBreakCount int32
+)
+
+// A lineBreak is a breakpoint set with "break <line>".
+type lineBreak struct {
+	// id numbers the breakpoint from 1, in the order they were set, for
+	// info breakpoints, delete, enable and disable, and RemoveBreakpoint.
+	id int
+
+	hits int // how many times it has paused the program, guarded by lineBreaksMu
+
+	file *sourceFile
+	line int
+
+	// goroutine, if set, is the label or id of the only goroutine the
+	// breakpoint pauses. It is looked up when the line is reached, so the
+	// goroutine need not exist when the breakpoint is set.
+	goroutine string
+
+	// name, if set, names the group of breakpoints this one belongs to, for
+	// "enable group" and the like. disabled is set by "disable group". Both
+	// are guarded by lineBreaksMu.
+	name     string
+	disabled bool
+
+	// cond, if set, is a Go expression that must be true for the breakpoint
+	// to pause. It is evaluated in the scope of the line each time the line
+	// is reached.
+	cond string
+
+	// temporary is set by tbreak. The breakpoint is deleted the first time
+	// it pauses.
+	temporary bool
+
+	// ignore is how many more times the breakpoint is reached, with its
+	// condition true, before it pauses. It is guarded by lineBreaksMu.
+	ignore int
+}
+
+// String describes b the way info breakpoints lists it.
+func (b *lineBreak) String() string {
+	s := fmt.Sprintf("line %d of %s", b.line, b.file.name)
+	if b.goroutine != "" {
+		s += " for goroutine " + b.goroutine
+	}
+	if b.name != "" {
+		s += ", named " + b.name
+	}
+	if b.cond != "" {
+		s += ", if " + b.cond
+	}
+	if b.temporary {
+		s += ", temporary"
+	}
+	if b.ignore > 0 {
+		s += ", ignoring the next " + nHits(b.ignore)
+	}
+	if b.disabled {
+		s += " (disabled)"
+	}
+	return s
+}
+
+// lineBreaks holds the breakpoints set with "break <line>". Like returnBreaks,
+// it is guarded by a mutex, and lineBreakCount lets goroutines skip the lock.
+var (
+	lineBreaksMu   sync.Mutex
+	lineBreaks     []*lineBreak
+	lineBreakCount int32
+	lastBreakID    int // guarded by lineBreaksMu
+)
+
+// A BreakpointOption configures a breakpoint set with AddBreakpoint.
+type BreakpointOption func(*lineBreak)
+
+// BreakGoroutine makes a breakpoint pause only the goroutine with the given
+// label or id, like "break <line> goroutine <label>" at the prompt.
+func BreakGoroutine(label string) BreakpointOption {
+	return func(b *lineBreak) { b.goroutine = label }
+}
+
+// BreakName puts a breakpoint in the group of that name, like
+// "break <line> name <name>" at the prompt.
+func BreakName(name string) BreakpointOption {
+	return func(b *lineBreak) { b.name = name }
+}
+
+// BreakIf makes a breakpoint pause only when the Go expression cond is true,
+// like "break <line> if <cond>" at the prompt. cond is evaluated each time
+// the line is reached, in the scope of the line, as print would evaluate it.
+func BreakIf(cond string) BreakpointOption {
+	return func(b *lineBreak) { b.cond = cond }
+}
+
+// BreakTemporary makes a breakpoint delete itself the first time it pauses,
+// like "tbreak <line>" at the prompt.
+func BreakTemporary() BreakpointOption {
+	return func(b *lineBreak) { b.temporary = true }
+}
+
+// BreakIgnore makes a breakpoint pass up the first n times it would pause,
+// like "ignore <breakpoint> <n>" at the prompt.
+func BreakIgnore(n int) BreakpointOption {
+	return func(b *lineBreak) { b.ignore = n }
+}
+
+// AddBreakpoint sets a breakpoint on a line of a file godebug generated code
+// for, as the break command does at the prompt, so that tools and tests can
+// set up a session without typing commands. The file may be given by the end
+// of its path, such as "main.go", as long as only one generated file matches.
+// AddBreakpoint may be called from any goroutine, before or while the program
+// runs generated code, and returns an id to pass to RemoveBreakpoint.
+func AddBreakpoint(file string, line int, opts ...BreakpointOption) (id int, err error) {
+	f, err := lookupSourceFile(file)
+	if err != nil {
+		return 0, err
+	}
+	b, err := addBreakpoint(f, line, opts)
+	if err != nil {
+		return 0, err
+	}
+	return b.id, nil
+}
+
+// RemoveBreakpoint deletes the breakpoint with the given id, as returned by
+// AddBreakpoint. It does nothing if there is no such breakpoint, e.g. because
+// it was already deleted at the prompt.
+func RemoveBreakpoint(id int) {
+	lineBreaksMu.Lock()
+	defer lineBreaksMu.Unlock()
+	for _, b := range lineBreaks {
+		if b.id == id {
+			removeLineBreak(b)
+			return
+		}
+	}
+}
+
+// removeLineBreak deletes b, if it is still set. lineBreaksMu must be held.
+func removeLineBreak(b *lineBreak) {
+	for i, other := range lineBreaks {
+		if other == b {
+			lineBreaks = append(lineBreaks[:i], lineBreaks[i+1:]...)
+			atomic.StoreInt32(&lineBreakCount, int32(len(lineBreaks)))
+			return
+		}
+	}
+}
+
+// addBreakpoint sets a breakpoint on the given line of file.
+func addBreakpoint(file *sourceFile, line int, opts []BreakpointOption) (*lineBreak, error) {
+	if n := len(file.text()); line < 1 || line > n {
+		return nil, fmt.Errorf("godebug: %s has no line %d; it has %d lines", file.name, line, n)
+	}
+	b := &lineBreak{file: file, line: line}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.cond != "" {
+		if _, err := parser.ParseExpr(b.cond); err != nil {
+			return nil, fmt.Errorf("godebug: cannot parse the condition %s: %v", b.cond, err)
+		}
+	}
+	addLineBreak(b)
+	return b, nil
+}
+
+const (
+	breakUsage  = "usage: break [<file>:]<line> [goroutine <label>] [name <name>] [if <condition>] or break return <function>"
+	tbreakUsage = "usage: tbreak [<file>:]<line> [goroutine <label>] [name <name>] [if <condition>]"
+)
+
+// splitCondition splits the arguments of break and tbreak into words and the
+// condition after "if", which is kept as it was typed, spaces and all. ok is
+// false if "if" is not followed by a condition.
+func splitCondition(arg string) (args []string, cond string, ok bool) {
+	if i := strings.Index(arg+" ", " if "); i >= 0 {
+		arg, cond = arg[:i], strings.TrimSpace(arg[i+len(" if"):])
+		if cond == "" {
+			return nil, "", false
+		}
+	}
+	return strings.Fields(arg), cond, true
+}
+
+func breakCommand(s *Scope, arg string) {
+	args, cond, ok := splitCondition(arg)
+	if !ok || len(args) == 0 {
+		fmt.Fprintln(output, breakUsage)
+		return
+	}
+	if args[0] != "return" {
+		breakLineCommand(s, args, cond, breakUsage)
+		return
+	}
+	if len(args) != 2 || cond != "" {
+		fmt.Fprintln(output, breakUsage)
+		return
+	}
+	name := args[1]
+	returnBreaksMu.Lock()
+	defer returnBreaksMu.Unlock()
+	if !returnBreaks[name] {
+		returnBreaks[name] = true
+		atomic.AddInt32(&returnBreakCount, 1)
+	}
+	fmt.Fprintf(output, "Breakpoint set on return from %s().\n", name)
+}
+
+// breakOnReturn reports whether the function c was created for is one the user
+// asked to break on the return of. If it is, the debugger follows c's goroutine
+// from here on, just as it would after a "breakpoint" statement.
+func breakOnReturn(c *Context) bool {
+	if c.scope == nil || atomic.LoadInt32(&returnBreakCount) == 0 {
+		return false
+	}
+	name, ok := matchReturnBreak(c.funcName())
+	if !ok {
+		return false
+	}
+	if atomic.LoadInt32(&currentState) == run {
+		atomic.StoreUint32(&currentGoroutine, c.goroutine)
+		currentState = step
+		pauseReason = "break return " + name
+	} else if atomic.LoadUint32(&currentGoroutine) != c.goroutine {
+		return false
+	}
+	notify("break", fmt.Sprintf("< break on return from %s() >", name))
+	return true
+}
+
+// matchReturnBreak finds the breakpoint for the function with the given
+// package-qualified name. A breakpoint may leave out the package name.
+func matchReturnBreak(funcName string) (name string, ok bool) {
+	returnBreaksMu.Lock()
+	defer returnBreaksMu.Unlock()
+	if returnBreaks[funcName] {
+		return funcName, true
+	}
+	if i := strings.Index(funcName, "."); i >= 0 && returnBreaks[funcName[i+1:]] {
+		return funcName[i+1:], true
+	}
+	return "", false
+}
+
+// tbreakCommand implements "tbreak", which takes the same arguments as a
+// break on a line.
+func tbreakCommand(s *Scope, arg string) {
+	args, cond, ok := splitCondition(arg)
+	if !ok || len(args) == 0 {
+		fmt.Fprintln(output, tbreakUsage)
+		return
+	}
+	breakLineCommand(s, args, cond, tbreakUsage, BreakTemporary())
+}
+
+// breakLineCommand implements "break [<file>:]<line> [goroutine <label>]
+// [name <name>] [if <cond>]" and tbreak, which passes BreakTemporary in opts.
+// Without <file>, the line is in the file the program is paused in.
+func breakLineCommand(s *Scope, args []string, cond, usage string, opts ...BreakpointOption) {
+	if len(args)%2 != 1 {
+		fmt.Fprintln(output, usage)
+		return
+	}
+	key, ok := parseFileLine(s.file, args[0], usage)
+	if !ok {
+		return
+	}
+	for words := args[1:]; len(words) > 0; words = words[2:] {
+		switch words[0] {
+		case "goroutine":
+			opts = append(opts, BreakGoroutine(words[1]))
+		case "name":
+			opts = append(opts, BreakName(words[1]))
+		default:
+			fmt.Fprintln(output, usage)
+			return
+		}
+	}
+	if cond != "" {
+		opts = append(opts, BreakIf(cond))
+	}
+	b, err := addBreakpoint(key.file, key.line, opts)
+	if err != nil {
+		fmt.Fprintln(output, err)
+		return
+	}
+	what := "Breakpoint"
+	if b.temporary {
+		what = "Temporary breakpoint"
+	}
+	msg := fmt.Sprintf("%s set at line %d", what, key.line)
+	if key.file != s.file {
+		msg += " of " + key.file.name
+	}
+	if b.goroutine != "" {
+		msg += " for goroutine " + b.goroutine
+	}
+	if b.name != "" {
+		msg += ", named " + b.name
+	}
+	if b.cond != "" {
+		msg += ", if " + b.cond
+	}
+	fmt.Fprintln(output, msg+".")
+}
+
+func addLineBreak(b *lineBreak) {
+	lineBreaksMu.Lock()
+	lastBreakID++
+	b.id = lastBreakID
+	lineBreaks = append(lineBreaks, b)
+	lineBreaksMu.Unlock()
+	trackLine(b.file, b.line)
+	atomic.AddInt32(&lineBreakCount, 1)
+}
+
+// envBreaks holds the breakpoints listed in GODEBUG_BREAK, like
+// "main.go:42,server.go:88", until the first line of generated code runs and
+// envBreaksArmed is cleared. By then the package variables that hold the
+// generated files have been initialized, so the files can be looked up.
+var (
+	envBreaks      []string
+	envBreaksArmed int32
+	envBreaksOnce  sync.Once
+)
+
+// setEnvBreaks sets the breakpoints in envBreaks. Entries that name no line
+// are reported and skipped.
+func setEnvBreaks() {
+	envBreaksOnce.Do(func() {
+		for _, entry := range envBreaks {
+			key, ok := parseFileLine(nil, entry, "usage: GODEBUG_BREAK=<file>:<line>,<file>:<line>...")
+			if !ok {
+				fmt.Fprintf(output, "Ignoring %q in GODEBUG_BREAK.\n", entry)
+				continue
+			}
+			addLineBreak(&lineBreak{file: key.file, line: key.line})
+		}
+		atomic.StoreInt32(&envBreaksArmed, 0)
+	})
+}
+
+// hitLineBreak reports whether c's goroutine should pause at a breakpoint on
+// the given line. If the program is running, the debugger follows c's
+// goroutine from here on. A breakpoint does nothing while the debugger is
+// following another goroutine, or when step has already paused at the line.
+// A temporary breakpoint is deleted when it pauses.
+func hitLineBreak(c *Context, s *Scope, line int) bool {
+	if s.file == nil {
+		return false
+	}
+	if atomic.LoadInt32(&currentState) != run && (atomic.LoadUint32(&currentGoroutine) != c.goroutine || c == lastPause.ctx && line == lastPause.line) {
+		return false
+	}
+	b := matchLineBreak(c, s, line)
+	if b == nil {
+		return false
+	}
+	if atomic.LoadInt32(&currentState) == run {
+		atomic.StoreUint32(&currentGoroutine, c.goroutine)
+		currentState = step
+		pauseReason = fmt.Sprintf("breakpoint %d", b.id)
+		if b.temporary {
+			notify("break", fmt.Sprintf("< temporary breakpoint at line %d, now deleted >", line))
+		} else {
+			notify("break", fmt.Sprintf("< breakpoint at line %d >", line))
+		}
+	}
+	lineBreaksMu.Lock()
+	b.hits++
+	if b.temporary {
+		removeLineBreak(b)
+	}
+	lineBreaksMu.Unlock()
+	return true
+}
+
+// breakMarks returns the marks list shows for the lines of file that have
+// breakpoints: 'B', 'b' for a conditional breakpoint, one with an if
+// condition or for only one goroutine, or '-' for a disabled one. A line with several breakpoints gets the first of
+// these that applies.
+func breakMarks(file *sourceFile) map[int]rune {
+	lineBreaksMu.Lock()
+	defer lineBreaksMu.Unlock()
+	rank := map[rune]int{'B': 3, 'b': 2, '-': 1}
+	marks := make(map[int]rune)
+	for _, b := range lineBreaks {
+		if b.file != file {
+			continue
+		}
+		m := 'B'
+		switch {
+		case b.disabled:
+			m = '-'
+		case b.cond != "" || b.goroutine != "":
+			m = 'b'
+		}
+		if rank[m] > rank[marks[b.line]] {
+			marks[b.line] = m
+		}
+	}
+	return marks
+}
+
+// matchLineBreak returns the breakpoint on the line for c's goroutine whose
+// condition, if it has one, holds, or nil if there is none.
+func matchLineBreak(c *Context, s *Scope, line int) *lineBreak {
+	lineBreaksMu.Lock()
+	var found []*lineBreak
+	for _, b := range lineBreaks {
+		if b.file != s.file || b.line != line || b.disabled {
+			continue
+		}
+		if b.goroutine == "" || b.goroutine == goroutineLabel(c.goroutine) || b.goroutine == strconv.FormatUint(uint64(c.goroutine), 10) {
+			found = append(found, b)
+		}
+	}
+	lineBreaksMu.Unlock()
+	// Conditions are evaluated without the lock, since they may call
+	// functions that reach breakpoints of their own.
+	for _, b := range found {
+		if (b.cond == "" || conditionHolds(s, b)) && !ignoreHit(b) {
+			return b
+		}
+	}
+	return nil
+}
+
+// ignoreHit reports whether b passes up this hit because of an ignore count,
+// and counts it down if so.
+func ignoreHit(b *lineBreak) bool {
+	lineBreaksMu.Lock()
+	defer lineBreaksMu.Unlock()
+	if b.ignore == 0 {
+		return false
+	}
+	b.ignore--
+	return true
+}
+
+// nHits formats n as "1 hit" or "<n> hits".
+func nHits(n int) string {
+	if n == 1 {
+		return "1 hit"
+	}
+	return strconv.Itoa(n) + " hits"
+}
+
+const ignoreCountUsage = "usage: ignore <n> <count>, where <n> is a breakpoint number"
+
+// ignoreCommand implements "ignore <n> <count>".
+func ignoreCommand(args string) {
+	words := strings.Fields(args)
+	if len(words) != 2 {
+		fmt.Fprintln(output, ignoreCountUsage)
+		return
+	}
+	id, err := strconv.Atoi(words[0])
+	count, err2 := strconv.Atoi(words[1])
+	if err != nil || err2 != nil || count < 0 {
+		fmt.Fprintln(output, ignoreCountUsage)
+		return
+	}
+	lineBreaksMu.Lock()
+	defer lineBreaksMu.Unlock()
+	for _, b := range lineBreaks {
+		if b.id != id {
+			continue
+		}
+		b.ignore = count
+		if count == 0 {
+			fmt.Fprintf(output, "Breakpoint %d will pause the next time it is hit.\n", id)
+		} else {
+			fmt.Fprintf(output, "Breakpoint %d will ignore the next %s.\n", id, nHits(count))
+		}
+		return
+	}
+	fmt.Fprintf(output, "There is no breakpoint %d; info breakpoints lists them.\n", id)
+}
+
+// conditionHolds evaluates the condition of b. A condition that cannot be
+// evaluated, or is not a bool, pauses the program, so that the mistake is seen.
+func conditionHolds(s *Scope, b *lineBreak) bool {
+	v, err := evalOne(s, b.cond)
+	switch {
+	case err != nil:
+		notify("break", fmt.Sprintf("< cannot evaluate the condition of breakpoint %d, %s: %v >", b.id, b.cond, err))
+		return true
+	case v.Kind() != reflect.Bool:
+		notify("break", fmt.Sprintf("< the condition of breakpoint %d, %s, is not a bool >", b.id, b.cond))
+		return true
+	}
+	return v.Bool()
+}
+
+// groupCommand implements "enable group", "disable group" and "delete group",
+// which act on the breakpoints set with "name <name>".
+func groupCommand(verb, name string) {
+	if name == "" {
+		fmt.Fprintf(output, "usage: %s group <name>\n", verb)
+		return
+	}
+	lineBreaksMu.Lock()
+	n := 0
+	kept := lineBreaks[:0]
+	for _, b := range lineBreaks {
+		if b.name != name {
+			kept = append(kept, b)
+			continue
+		}
+		n++
+		switch verb {
+		case "enable":
+			b.disabled = false
+		case "disable":
+			b.disabled = true
+		case "delete":
+			continue
+		}
+		kept = append(kept, b)
+	}
+	for i := len(kept); i < len(lineBreaks); i++ {
+		lineBreaks[i] = nil
+	}
+	lineBreaks = kept
+	atomic.StoreInt32(&lineBreakCount, int32(len(lineBreaks)))
+	lineBreaksMu.Unlock()
+	done := map[string]string{"enable": "Enabled", "disable": "Disabled", "delete": "Deleted"}[verb]
+	switch n {
+	case 0:
+		fmt.Fprintf(output, "No breakpoints are named %s.\n", name)
+	case 1:
+		fmt.Fprintf(output, "%s 1 breakpoint named %s.\n", done, name)
+	default:
+		fmt.Fprintf(output, "%s %d breakpoints named %s.\n", done, n, name)
+	}
+}
+
+const breakIDsUsage = "usage: %s <n>..., where each <n> is a breakpoint number or a range like 2-4"
+
+// idsCommand implements "delete", "enable" and "disable", which act on the
+// breakpoints with the given numbers, as info breakpoints lists them.
+func idsCommand(verb, args string) {
+	ranges, ok := parseBreakIDs(args)
+	if !ok {
+		fmt.Fprintf(output, breakIDsUsage+"\n", verb)
+		return
+	}
+	done := map[string]string{"enable": "Enabled", "disable": "Disabled", "delete": "Deleted"}[verb]
+	lineBreaksMu.Lock()
+	defer lineBreaksMu.Unlock()
+	for _, r := range ranges {
+		// Only the breakpoints that exist are looked at, so a range costs no
+		// more than the breakpoints it holds, however wide it is.
+		var bs []*lineBreak
+		for _, b := range lineBreaks {
+			if b.id >= r.first && b.id <= r.last {
+				bs = append(bs, b)
+			}
+		}
+		switch {
+		case len(bs) > 0:
+		case r.first == r.last:
+			fmt.Fprintf(output, "There is no breakpoint %d; info breakpoints lists them.\n", r.first)
+		default:
+			fmt.Fprintf(output, "There are no breakpoints %d-%d; info breakpoints lists them.\n", r.first, r.last)
+		}
+		for _, b := range bs {
+			switch {
+			case verb == "delete":
+				removeLineBreak(b)
+				fmt.Fprintf(output, "%s breakpoint %d.\n", done, b.id)
+			case b.disabled == (verb == "disable"):
+				fmt.Fprintf(output, "Breakpoint %d is already %s.\n", b.id, strings.ToLower(done))
+			default:
+				b.disabled = verb == "disable"
+				fmt.Fprintf(output, "%s breakpoint %d.\n", done, b.id)
+			}
+		}
+	}
+}
+
+// An idRange is the breakpoint numbers from first to last, inclusive.
+type idRange struct {
+	first, last int
+}
+
+// parseBreakIDs parses a list of breakpoint numbers and ranges of them, like
+// "1 3-5", in the order given. A single number is a range of one.
+func parseBreakIDs(args string) (ranges []idRange, ok bool) {
+	words := strings.Fields(args)
+	if len(words) == 0 {
+		return nil, false
+	}
+	for _, w := range words {
+		from, to := w, w
+		if i := strings.Index(w, "-"); i > 0 {
+			from, to = w[:i], w[i+1:]
+		}
+		first, err := strconv.Atoi(from)
+		if err != nil || first < 1 {
+			return nil, false
+		}
+		last, err := strconv.Atoi(to)
+		if err != nil || last < first {
+			return nil, false
+		}
+		ranges = append(ranges, idRange{first, last})
+	}
+	return ranges, true
+}
+
+// breakpointDescs describes the line breakpoints, in the order they were
+// set, then the return breakpoints and the channel breakpoints.
+func breakpointDescs() []string {
+	var descs []string
+	lineBreaksMu.Lock()
+	for _, b := range lineBreaks {
+		descs = append(descs, b.String())
+	}
+	lineBreaksMu.Unlock()
+	return append(descs, otherBreakpointDescs()...)
+}
+
+// otherBreakpointDescs describes the return breakpoints and the channel
+// breakpoints, which have no numbers.
+func otherBreakpointDescs() []string {
+	var descs []string
+	returnBreaksMu.Lock()
+	names := make([]string, 0, len(returnBreaks))
+	for name := range returnBreaks {
+		names = append(names, name)
+	}
+	returnBreaksMu.Unlock()
+	sort.Strings(names)
+	for _, name := range names {
+		descs = append(descs, "return from "+name+"()")
+	}
+	chanBreaksMu.Lock()
+	for _, b := range chanBreaks {
+		descs = append(descs, "next send or receive on "+b.name)
+	}
+	chanBreaksMu.Unlock()
+	return descs
+}
+
+// printBreakpoints implements "info breakpoints". Line breakpoints are
+// listed with their numbers and how many times they have paused.
+func printBreakpoints() {
+	var descs []string
+	lineBreaksMu.Lock()
+	for _, b := range lineBreaks {
+		times := "times"
+		if b.hits == 1 {
+			times = "time"
+		}
+		descs = append(descs, fmt.Sprintf("%d: %s; hit %d %s", b.id, b, b.hits, times))
+	}
+	lineBreaksMu.Unlock()
+	descs = append(descs, otherBreakpointDescs()...)
+	if len(descs) == 0 {
+		fmt.Fprintln(output, "No breakpoints.")
+	}
+	for _, desc := range descs {
+		fmt.Fprintln(output, desc)
+	}
+}
+
+// startTime approximates when the program started, for "break at <duration>".
+var startTime = time.Now()
+
+// timeBreakArmed is set once the time given to "break at" has come. The first
+// goroutine to run a line of generated code after that clears it and pauses.
+var timeBreakArmed int32
+
+// timeBreak holds the timer for the pending "break at", if any.
+var timeBreak struct {
+	sync.Mutex
+	timer *time.Timer
+	desc  string
+}
+
+// breakAtCommand implements "break at <duration|time>".
+func breakAtCommand(arg string) {
+	deadline, desc, err := parseBreakTime(arg)
+	if err != nil {
+		fmt.Fprintln(output, err)
+		return
+	}
+	timeBreak.Lock()
+	defer timeBreak.Unlock()
+	if timeBreak.timer != nil {
+		timeBreak.timer.Stop()
+	}
+	timeBreak.desc = desc
+	d := time.Until(deadline)
+	if d <= 0 {
+		timeBreak.timer = nil
+		atomic.StoreInt32(&timeBreakArmed, 1)
+		fmt.Fprintln(output, "That time has passed. Will pause at the next line of generated code any goroutine runs.")
+		return
+	}
+	timeBreak.timer = time.AfterFunc(d, fireTimeBreak)
+	fmt.Fprintf(output, "Will pause %s.\n", desc)
+}
+
+// parseBreakTime parses a duration since the program started, like "30s", or
+// a wall-clock time, like "15:04:05" for today or an RFC 3339 time.
+func parseBreakTime(arg string) (deadline time.Time, desc string, err error) {
+	if d, err := time.ParseDuration(arg); err == nil {
+		return startTime.Add(d), fmt.Sprintf("after the program has run for %v", d), nil
+	}
+	if t, err := time.ParseInLocation("15:04:05", arg, time.Local); err == nil {
+		y, m, d := time.Now().Date()
+		t = time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, time.Local)
+		return t, "at " + arg, nil
+	}
+	if t, err := time.Parse(time.RFC3339, arg); err == nil {
+		return t, "at " + arg, nil
+	}
+	return time.Time{}, "", fmt.Errorf("usage: break at <duration|time>, e.g. 30s, 15:04:05 or %s", time.RFC3339)
+}
+
+// fireTimeBreak runs when the time given to "break at" comes. If no goroutine
+// takes the break within a second, it says why the debugger has not paused.
+func fireTimeBreak() {
+	timeBreak.Lock()
+	desc := timeBreak.desc
+	timeBreak.timer = nil
+	timeBreak.Unlock()
+	atomic.StoreInt32(&timeBreakArmed, 1)
+	time.Sleep(time.Second)
+	if atomic.LoadInt32(&timeBreakArmed) == 1 {
+		notify("break", fmt.Sprintf("< break %s reached, but no generated code has run since; waiting for the next line >", desc))
+	}
+}
+
+// takeTimeBreak is called when the time given to "break at" has come. If c's
+// goroutine is the first to run generated code since, the debugger follows it
+// from here on, just as it would after a "breakpoint" statement. If the
+// debugger is already stepping, the break is dropped.
+func takeTimeBreak(c *Context) {
+	if !atomic.CompareAndSwapInt32(&timeBreakArmed, 1, 0) || atomic.LoadInt32(&currentState) != run {
+		return
+	}
+	atomic.StoreUint32(&currentGoroutine, c.goroutine)
+	lastPause.ctx = nil
+	currentState = step
+	pauseReason = "break at"
+	notify("break", "< break at time reached >")
+}
+
+// startBreakArmed is set by SetBreakAtStart, or by GODEBUG_BREAK_AT_START=1 in
+// the environment. The first goroutine to run a line of generated code after
+// that clears it and pauses.
+var startBreakArmed int32
+
+func init() {
+	if os.Getenv("GODEBUG_BREAK_AT_START") == "1" {
+		startBreakArmed = 1
+	}
+	for _, entry := range strings.Split(os.Getenv("GODEBUG_BREAK"), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			envBreaks = append(envBreaks, entry)
+			envBreaksArmed = 1
+		}
+	}
+}
+
+// SetBreakAtStart makes the debugger pause at the next line of generated code
+// any goroutine runs, as if a breakpoint were there. Called from an init
+// function, or set with GODEBUG_BREAK_AT_START=1 in the environment, it pauses
+// at the first line of the program without editing its source. If several
+// goroutines race to run generated code, the debugger follows the first and
+// says which it is. SetBreakAtStart(false) cancels it if it has not fired.
+func SetBreakAtStart(on bool) {
+	if on {
+		atomic.StoreInt32(&startBreakArmed, 1)
+	} else {
+		atomic.StoreInt32(&startBreakArmed, 0)
+	}
+}
+
+// takeStartBreak is like takeTimeBreak, for SetBreakAtStart.
+func takeStartBreak(c *Context) {
+	if !atomic.CompareAndSwapInt32(&startBreakArmed, 1, 0) || atomic.LoadInt32(&currentState) != run {
+		return
+	}
+	atomic.StoreUint32(&currentGoroutine, c.goroutine)
+	lastPause.ctx = nil
+	currentState = step
+	pauseReason = "break at start"
+	notify("break", fmt.Sprintf("< break at start, in goroutine %s >", goroutineName(c.goroutine)))
+}
@@ -0,0 +1,38 @@
+package godebug
+
+// This file implements "info build", which describes the program for bug
+// reports.
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// printBuildInfo implements "info build".
+func printBuildInfo() {
+	fmt.Fprintf(output, "go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Path != "" {
+			fmt.Fprintf(output, "main module: %s\n", moduleString(&info.Main))
+		}
+		for _, m := range info.Deps {
+			if m.Path == "github.com/mailgun/godebug" {
+				fmt.Fprintf(output, "godebug: %s\n", moduleString(m))
+			}
+		}
+	}
+	sourceFiles.Lock()
+	n := len(sourceFiles.list)
+	sourceFiles.Unlock()
+	fmt.Fprintf(output, "generated files: %d\n", n)
+}
+
+// moduleString describes m the way go version -m does.
+func moduleString(m *debug.Module) string {
+	s := m.Path + " " + m.Version
+	if m.Replace != nil {
+		s += " => " + moduleString(m.Replace)
+	}
+	return s
+}
@@ -0,0 +1,79 @@
+package godebug
+
+// This file implements "info calls" and "reset calls".
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// calls counts the calls to each generated function, keyed by the pc its
+// godebug entrypoint was called from, which its Context already holds. Each
+// count is a *uint64 added to atomically, so that goroutines calling
+// functions do not wait on each other. Names are only looked up when the
+// counts are printed, to keep counting cheap.
+var calls sync.Map
+
+func countCall(pc uintptr) {
+	n, ok := calls.Load(pc)
+	if !ok {
+		n, _ = calls.LoadOrStore(pc, new(uint64))
+	}
+	atomic.AddUint64(n.(*uint64), 1)
+}
+
+type callCount struct {
+	name  string
+	count uint64
+}
+
+// callCounts returns the number of calls to each function, most called first.
+func callCounts() []callCount {
+	byName := make(map[string]uint64)
+	calls.Range(func(pc, n interface{}) bool {
+		if n := atomic.LoadUint64(n.(*uint64)); n > 0 {
+			byName[funcNameForPC(pc.(uintptr))] += n
+		}
+		return true
+	})
+	counts := make([]callCount, 0, len(byName))
+	for name, n := range byName {
+		counts = append(counts, callCount{name, n})
+	}
+	sort.Sort(byCount(counts))
+	return counts
+}
+
+func printCallCounts() {
+	counts := callCounts()
+	if len(counts) == 0 {
+		fmt.Fprintln(output, "No calls counted.")
+		return
+	}
+	width := len(strconv.FormatUint(counts[0].count, 10))
+	for _, c := range counts {
+		fmt.Fprintf(output, "%*d %s\n", width, c.count, c.name)
+	}
+}
+
+func resetCallCounts() {
+	calls.Range(func(_, n interface{}) bool {
+		atomic.StoreUint64(n.(*uint64), 0)
+		return true
+	})
+	fmt.Fprintln(output, "Call counts reset.")
+}
+
+type byCount []callCount
+
+func (c byCount) Len() int      { return len(c) }
+func (c byCount) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c byCount) Less(i, j int) bool {
+	if c[i].count != c[j].count {
+		return c[i].count > c[j].count
+	}
+	return c[i].name < c[j].name
+}
@@ -0,0 +1,178 @@
+package godebug
+
+// This file implements the "output" command, which keeps what the program
+// writes to os.Stdout and os.Stderr apart from what the debugger prints.
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// flushMarker is written to the capturing pipe to learn when everything
+// written before it has been read.
+const flushMarker = "\x00godebug: output flushed\x00"
+
+// captured holds the program's output while "output on" is in effect. Both
+// os.Stdout and os.Stderr are set to the write end of one pipe, so that the
+// program's output keeps its order, and a goroutine copies from the read end
+// to buf, so that the program never blocks on a full pipe.
+var captured struct {
+	sync.Mutex
+	stdout, stderr *os.File // what os.Stdout and os.Stderr were before
+	w              *os.File
+	buf            bytes.Buffer
+	flushed        chan struct{} // receives when the copier reads flushMarker
+	done           chan struct{} // closed when the copier reaches the end of the pipe
+}
+
+// flushMu lets one flush at a time wait for its marker.
+var flushMu sync.Mutex
+
+// outputCommand implements "output", "output on" and "output off".
+func outputCommand(args string) {
+	switch args {
+	case "":
+		showCaptured()
+	case "on":
+		if err := startCapture(); err != nil {
+			fmt.Fprintln(output, err)
+		}
+	case "off":
+		stopCapture()
+	default:
+		fmt.Fprintln(output, "usage: output [on|off]")
+	}
+}
+
+func startCapture() error {
+	captured.Lock()
+	defer captured.Unlock()
+	if captured.w != nil {
+		fmt.Fprintln(output, "Already capturing the program's output.")
+		return nil
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	captured.stdout, captured.stderr = os.Stdout, os.Stderr
+	captured.w = w
+	captured.flushed = make(chan struct{}, 1)
+	captured.done = make(chan struct{})
+	os.Stdout, os.Stderr = w, w
+	go copyCaptured(r, captured.flushed, captured.done)
+	fmt.Fprintln(output, "Capturing the program's output. Enter output to see it.")
+	return nil
+}
+
+// copyCaptured copies the program's output from r to captured.buf until the
+// pipe is closed.
+func copyCaptured(r *os.File, flushed, done chan struct{}) {
+	defer close(done)
+	defer r.Close()
+	var pending []byte
+	chunk := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(chunk)
+		pending = append(pending, chunk[:n]...)
+		for {
+			i := bytes.Index(pending, []byte(flushMarker))
+			if i < 0 {
+				break
+			}
+			captured.Lock()
+			captured.buf.Write(pending[:i])
+			captured.Unlock()
+			pending = pending[i+len(flushMarker):]
+			flushed <- struct{}{}
+		}
+		// Keep back what may be the start of a marker.
+		keep := len(flushMarker) - 1
+		if keep > len(pending) {
+			keep = len(pending)
+		}
+		for keep > 0 && !bytes.HasPrefix([]byte(flushMarker), pending[len(pending)-keep:]) {
+			keep--
+		}
+		captured.Lock()
+		captured.buf.Write(pending[:len(pending)-keep])
+		captured.Unlock()
+		pending = pending[len(pending)-keep:]
+		if err != nil {
+			captured.Lock()
+			captured.buf.Write(pending)
+			captured.Unlock()
+			if err != io.EOF {
+				fmt.Fprintf(output, "godebug: capturing the program's output: %v\n", err)
+			}
+			return
+		}
+	}
+}
+
+// flushCaptured waits until everything the program wrote before the call is
+// in captured.buf.
+func flushCaptured(w *os.File, flushed chan struct{}) {
+	flushMu.Lock()
+	defer flushMu.Unlock()
+	if _, err := io.WriteString(w, flushMarker); err != nil {
+		return
+	}
+	<-flushed
+}
+
+// showCaptured implements "output". It prints what the program wrote since
+// the last time.
+func showCaptured() {
+	captured.Lock()
+	w, flushed := captured.w, captured.flushed
+	captured.Unlock()
+	if w == nil {
+		fmt.Fprintln(output, "The program's output is not being captured. Enter output on to capture it.")
+		return
+	}
+	flushCaptured(w, flushed)
+	captured.Lock()
+	b := captured.buf.Bytes()
+	captured.buf.Reset()
+	captured.Unlock()
+	if len(b) == 0 {
+		fmt.Fprintln(output, "No new output from the program.")
+		return
+	}
+	output.Write(b)
+	if b[len(b)-1] != '\n' {
+		fmt.Fprintln(output)
+	}
+}
+
+// stopCapture restores os.Stdout and os.Stderr. Output that was captured but
+// not shown is written to the restored os.Stdout.
+func stopCapture() {
+	captured.Lock()
+	w, done := captured.w, captured.done
+	if w == nil {
+		captured.Unlock()
+		fmt.Fprintln(output, "The program's output is not being captured.")
+		return
+	}
+	os.Stdout, os.Stderr = captured.stdout, captured.stderr
+	captured.w = nil
+	captured.Unlock()
+	w.Close()
+	<-done
+	captured.Lock()
+	captured.buf.WriteTo(os.Stdout)
+	captured.Unlock()
+	fmt.Fprintln(output, "Stopped capturing the program's output.")
+}
+
+// capturing reports whether the program's output is being captured.
+func capturing() bool {
+	captured.Lock()
+	defer captured.Unlock()
+	return captured.w != nil
+}
@@ -0,0 +1,71 @@
+package godebug
+
+// This file implements "catch nil-deref", which pauses a goroutine that has
+// dereferenced a nil pointer before the panic unwinds its generated functions.
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// catchNilDeref is 1 while "catch nil-deref" is on.
+var catchNilDeref int32
+
+func catchCommand(args []string) {
+	switch {
+	case len(args) == 1 && args[0] == "nil-deref":
+		atomic.StoreInt32(&catchNilDeref, 1)
+		fmt.Fprintln(output, "Will pause when a nil pointer dereference panics in generated code.")
+	case len(args) == 2 && args[0] == "nil-deref" && args[1] == "off":
+		atomic.StoreInt32(&catchNilDeref, 0)
+		fmt.Fprintln(output, "No longer pausing at nil pointer dereferences.")
+	default:
+		fmt.Fprintln(output, "usage: catch nil-deref [off]")
+	}
+}
+
+// caughtNilDeref is called as c's function returns. It reports whether the
+// function is returning because of a nil pointer dereference that has not been
+// caught yet. If it is, the debugger follows c's goroutine from here on, just
+// as it would after a "breakpoint" statement.
+//
+// The generator does not mark dereferences. Instead, the panic is recognized
+// from the stack of the deferred ExitFunc, which the runtime calls from
+// gopanic before the function's frame goes away, so its locals can still be
+// printed. c.line is the last line the function started, the one that panicked.
+func caughtNilDeref(c *Context) bool {
+	if c.scope == nil || atomic.LoadInt32(&catchNilDeref) == 0 || c.g.caught || !panickingOnNil() {
+		return false
+	}
+	if atomic.LoadInt32(&currentState) == run {
+		atomic.StoreUint32(&currentGoroutine, c.goroutine)
+		currentState = step
+		pauseReason = "catch nil-deref"
+	} else if atomic.LoadUint32(&currentGoroutine) != c.goroutine {
+		return false
+	}
+	// Only the innermost generated function pauses. The flag is cleared at the
+	// goroutine's next line, in case the panic is recovered.
+	c.g.caught = true
+	notify("break", fmt.Sprintf("< nil pointer dereference at line %d; the panic goes on when you resume >", c.line))
+	return true
+}
+
+// panickingOnNil reports whether ExitFunc was called by a panic from a nil
+// pointer dereference, rather than by a deferred call that the panic runs.
+func panickingOnNil() bool {
+	var pcs [8]uintptr
+	n := runtime.Callers(4, pcs[:]) // skip runtime.Callers, panickingOnNil, caughtNilDeref and ExitFunc
+	frames := runtime.CallersFrames(pcs[:n])
+	f, more := frames.Next()
+	for more && strings.Contains(f.Function, ".deferwrap") {
+		f, more = frames.Next()
+	}
+	if !more || f.Function != "runtime.gopanic" {
+		return false
+	}
+	f, _ = frames.Next()
+	return f.Function == "runtime.panicmem"
+}
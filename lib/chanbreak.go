@@ -0,0 +1,207 @@
+package godebug
+
+// This file implements "break chan", which pauses at the next send or receive
+// on a channel.
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// A chanBreak pauses the program the next time a line is about to send on or
+// receive from a channel.
+type chanBreak struct {
+	name string  // the variable it was set on
+	ch   uintptr // the channel
+}
+
+// chanBreaks is checked by every goroutine that runs generated code, so it is
+// guarded by a mutex. chanBreakCount lets them skip the lock when it is empty.
+var (
+	chanBreaksMu   sync.Mutex
+	chanBreaks     []*chanBreak
+	chanBreakCount int32
+)
+
+// A chanOp is a send or receive in the source of a line. expr is the channel
+// expression, an identifier or a chain of field selectors.
+type chanOp struct {
+	expr string
+	send bool
+}
+
+// chanOps caches the channel operations of each file by line, since finding
+// them takes parsing the file.
+var chanOps struct {
+	sync.Mutex
+	m map[*sourceFile]map[int][]chanOp
+}
+
+const breakChanUsage = "usage: break chan <variable>"
+
+// breakChanCommand implements "break chan <variable>".
+func breakChanCommand(s *Scope, args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(output, breakChanUsage)
+		return
+	}
+	name := args[0]
+	v, ok := resolveSelector(s, name)
+	if !ok {
+		fmt.Fprintf(output, "%s is not a variable in scope\n", name)
+		return
+	}
+	if v.Kind() != reflect.Chan {
+		fmt.Fprintf(output, "%s is a %s, not a channel\n", name, v.Type())
+		return
+	}
+	if v.IsNil() {
+		fmt.Fprintf(output, "%s is a nil channel, which is never ready\n", name)
+		return
+	}
+	chanBreaksMu.Lock()
+	chanBreaks = append(chanBreaks, &chanBreak{name: name, ch: v.Pointer()})
+	atomic.AddInt32(&chanBreakCount, 1)
+	chanBreaksMu.Unlock()
+	fmt.Fprintf(output, "Will pause at the next send or receive on %s.\n", name)
+}
+
+// resolveSelector finds the value of expr, which is a variable or a chain of
+// field selectors on one, like s.jobs, without evaluating it: it is cheap
+// enough to run at every line and safe to run on any goroutine.
+func resolveSelector(s *Scope, expr string) (reflect.Value, bool) {
+	parts := strings.Split(expr, ".")
+	ptr, ok := s.getVar(parts[0])
+	if !ok {
+		return reflect.Value{}, false
+	}
+	v := reflect.ValueOf(ptr).Elem()
+	for _, field := range parts[1:] {
+		if v.Kind() == reflect.Ptr && !v.IsNil() {
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+		if v = v.FieldByName(field); !v.IsValid() {
+			return reflect.Value{}, false
+		}
+	}
+	return v, true
+}
+
+// hitChanBreak reports whether the line is about to send on or receive from a
+// channel with a break chan on it. If it is, the break is used up, and the
+// debugger follows c's goroutine from here on, just as it would after a
+// "breakpoint" statement.
+//
+// Like length watches, channels are only checked by the goroutine the debugger
+// is following, or by any goroutine while the program is running freely. Only
+// the channel operations written in generated code are seen, and only those
+// whose channel is a variable or a field of one.
+func hitChanBreak(c *Context, s *Scope, line int) bool {
+	if atomic.LoadInt32(&currentState) != run && atomic.LoadUint32(&currentGoroutine) != c.goroutine {
+		return false
+	}
+	ops := chanOpsAt(s.file, line)
+	if len(ops) == 0 {
+		return false
+	}
+	chanBreaksMu.Lock()
+	defer chanBreaksMu.Unlock()
+	for _, op := range ops {
+		v, ok := resolveSelector(s, op.expr)
+		if !ok || v.Kind() != reflect.Chan || v.IsNil() {
+			continue
+		}
+		for i, b := range chanBreaks {
+			if b.ch != v.Pointer() {
+				continue
+			}
+			chanBreaks = append(chanBreaks[:i], chanBreaks[i+1:]...)
+			atomic.StoreInt32(&chanBreakCount, int32(len(chanBreaks)))
+			what := "receive from"
+			if op.send {
+				what = "send on"
+			}
+			notify("break", fmt.Sprintf("< about to %s %s (break chan %s) >", what, op.expr, b.name))
+			if atomic.LoadInt32(&currentState) == run {
+				atomic.StoreUint32(&currentGoroutine, c.goroutine)
+				currentState = step
+				pauseReason = "break chan " + b.name
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// chanOpsAt returns the channel operations on the given line of file.
+func chanOpsAt(file *sourceFile, line int) []chanOp {
+	chanOps.Lock()
+	defer chanOps.Unlock()
+	if chanOps.m == nil {
+		chanOps.m = make(map[*sourceFile]map[int][]chanOp)
+	}
+	byLine, ok := chanOps.m[file]
+	if !ok {
+		byLine = findChanOps(file)
+		chanOps.m[file] = byLine
+	}
+	return byLine[line]
+}
+
+// findChanOps finds the sends, receives and range loops in the source of
+// file whose channel is a variable or a chain of field selectors on one. A
+// range loop is only known to be over a channel when it runs.
+func findChanOps(file *sourceFile) map[int][]chanOp {
+	byLine := make(map[int][]chanOp)
+	// Parse the code that is running, not text from reload or a source provider.
+	text := strings.Join(file.embedded, "\n")
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", text, 0)
+	if err != nil {
+		return byLine
+	}
+	add := func(pos token.Pos, ch ast.Expr, send bool) {
+		if expr, ok := selectorString(ch); ok {
+			line := fset.Position(pos).Line
+			byLine[line] = append(byLine[line], chanOp{expr: expr, send: send})
+		}
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SendStmt:
+			add(n.Pos(), n.Chan, true)
+		case *ast.UnaryExpr:
+			if n.Op == token.ARROW {
+				add(n.Pos(), n.X, false)
+			}
+		case *ast.RangeStmt:
+			add(n.Pos(), n.X, false)
+		}
+		return true
+	})
+	return byLine
+}
+
+// selectorString returns e as text if it is an identifier or a chain of field
+// selectors on one.
+func selectorString(e ast.Expr) (string, bool) {
+	switch e := e.(type) {
+	case *ast.Ident:
+		return e.Name, true
+	case *ast.SelectorExpr:
+		x, ok := selectorString(e.X)
+		return x + "." + e.Sel.Name, ok
+	case *ast.ParenExpr:
+		return selectorString(e.X)
+	}
+	return "", false
+}
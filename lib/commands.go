@@ -0,0 +1,931 @@
+package godebug
+
+// This file holds the commands that can be entered at the prompt.
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A command is something the user can enter at the prompt.
+type command struct {
+	// name is the full name of the command. It may be several words long,
+	// as in "info locals".
+	name string
+
+	// abbrev, if set, can be typed in place of the first word of name.
+	abbrev string
+
+	// aliases are other spellings of the command that help does not show.
+	aliases []string
+
+	// formats is true if the first word may be followed by /format, as in "print/flags".
+	formats bool
+
+	// usage describes the arguments, e.g. "<expression>". A command with no
+	// usage takes no arguments.
+	usage string
+
+	// summary is the one-line description shown by help. details is shown
+	// in addition by "help <command>", and may have several lines.
+	summary string
+	details string
+
+	// run runs the command with the given arguments and reports whether the
+	// program should resume. If run is nil, the entry only documents a variant
+	// of another command.
+	run func(p prompt, format, args string) (resume bool)
+}
+
+// prompt describes where the program is paused.
+type prompt struct {
+	ctx   *Context
+	scope *Scope
+	line  int
+}
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{
+			name: "help", abbrev: "h", aliases: []string{"?"},
+			usage:   "[command]",
+			summary: "Print this help.",
+			details: "With a command name, print what that command does in more detail.",
+			run: func(p prompt, format, args string) bool {
+				if args == "" {
+					fmt.Fprintln(output, helpText())
+				} else {
+					printCommandHelp(args)
+				}
+				return false
+			},
+		},
+		{
+			name: "next", abbrev: "n",
+			usage:   "[count]",
+			summary: "Run the next line, or the next <count> lines of the current function.",
+			details: "Calls to other functions on the line run to completion without pausing, unless they reach a breakpoint.\n" +
+				"Lines run inside those calls do not add to the count. A breakpoint on the way ends the count early.",
+			run: func(p prompt, format, args string) bool {
+				n := 1
+				if args != "" {
+					var err error
+					if n, err = strconv.Atoi(args); err != nil || n < 1 {
+						fmt.Fprintln(output, "usage: next [count]")
+						return false
+					}
+				}
+				nextCount = n - 1
+				currentState = next
+				return true
+			},
+		},
+		{
+			name: "step", abbrev: "s",
+			summary: "Run for one step.",
+			details: "Unlike next, step pauses in any function called from the line that has been generated by godebug.\n" +
+				"With follow-spawn on, step at a go statement pauses in the goroutine it starts.",
+			run: func(p prompt, format, args string) bool {
+				if s, line := p.paused(); getSetting("follow-spawn") == "on" && isGoStatement(s.sourceLine(line)) {
+					armSpawn(p.ctx.goroutine)
+				}
+				currentState = step
+				return true
+			},
+		},
+		{
+			name: "step goroutine", abbrev: "s",
+			usage:   "<id>",
+			summary: "Run goroutine <id> until its next line and pause there. Resuming returns to the current goroutine.",
+			details: "The current goroutine waits where it is in the meantime. If goroutine <id> never reaches another line, the debugger waits forever.\n" +
+				"A goroutine named with godebug.LabelGoroutine may be given by its label instead of its id.",
+			run: func(p prompt, format, args string) bool {
+				if stepGoroutine(args) && pauseHandler == nil {
+					fmt.Fprintln(output, pauseBanner(p.ctx, p.scope, p.line, ""))
+				}
+				return false
+			},
+		},
+		{
+			name:    "ignore line",
+			usage:   "[<file>:]<line>",
+			summary: "Never pause at <line>, even when stepping, e.g. to step past a noisy logging call.",
+			details: "Without <file>, the line is in the current file. <file> may be the end of a generated file's path, such as main.go.\n" +
+				"Breakpoints on the line do not pause either. noignore undoes this.",
+			run: func(p prompt, format, args string) bool {
+				ignoreLineCommand(p.scope, args)
+				return false
+			},
+		},
+		{
+			name:    "noignore",
+			usage:   "[[<file>:]<line>]",
+			summary: "Let the debugger pause at the lines given to ignore line again, or only at <line>.",
+			run: func(p prompt, format, args string) bool {
+				noignoreCommand(p.scope, args)
+				return false
+			},
+		},
+		{
+			name:    "wait goroutine",
+			usage:   "<id>",
+			summary: "Run until goroutine <id> returns from its outermost call to generated code, and pause at the next line another goroutine runs.",
+			details: "Breakpoints reached in the meantime pause as usual, and the wait goes on after them.\n" +
+				"A goroutine named with godebug.LabelGoroutine may be given by its label instead of its id.",
+			run: func(p prompt, format, args string) bool {
+				return waitGoroutine(args)
+			},
+		},
+		{
+			name:    "skip",
+			summary: "Run the next line without running the calls it makes to functions godebug generated code for.",
+			details: "This can break the program: skipped calls return zero values, and their side effects do not happen.\n" +
+				"The rest of the line still runs, including arguments and calls to other code, such as the standard library.",
+			run: func(p prompt, format, args string) bool {
+				_, line := p.paused()
+				startSkip(p.ctx, line)
+				return true
+			},
+		},
+		{
+			name:    "finish",
+			summary: "Run until the current function returns, print what it returned, and pause in its caller.",
+			details: "Calls the function makes do not pause either, unless they reach a breakpoint, which ends the finish early.\n" +
+				"The caller pauses at the line that made the call, which may still have the rest of an expression to run. If the caller is not generated code, the program pauses at the next line that is.\n" +
+				"The values are not known for functions that call recover, which godebug runs differently.",
+			run: func(p prompt, format, args string) bool {
+				return finishCommand(p.ctx)
+			},
+		},
+		{
+			name:    "until",
+			usage:   "<line>",
+			summary: "Run until the current function reaches <line> of the current file, or returns.",
+			details: "This runs past the rest of a loop without setting and deleting a breakpoint. Only the current call counts:\n" +
+				"the line is not reached by other calls to the same function, such as recursive ones. A breakpoint on the way pauses first.",
+			run: func(p prompt, format, args string) bool {
+				s, _ := p.paused()
+				return untilCommand(p.ctx, s, args)
+			},
+		},
+		{
+			name: "continue", abbrev: "c",
+			summary: "Run until the next breakpoint.",
+			details: "With trace-policy set to queue or prompt, a goroutine that reached a breakpoint while the current one was followed may be followed next.",
+			run: func(p prompt, format, args string) bool {
+				currentState = run
+				takeTrace(p)
+				return true
+			},
+		},
+		{
+			name: "continue switch", abbrev: "c",
+			summary: "Run until a goroutine other than the current one runs a line of generated code, and follow that goroutine from there.",
+			details: "A breakpoint reached first pauses as usual and cancels the switch.",
+			run: func(p prompt, format, args string) bool {
+				continueSwitch()
+				return true
+			},
+		},
+		{
+			name:    "detach",
+			summary: "Let the program run on at close to full speed, without the debugger.",
+			details: "Breakpoints, watches and godebug.Break no longer pause the program, and output capturing and pprof labels are undone.\n" +
+				"The debugger cannot be attached again; restart the program to debug it again.",
+			run: func(p prompt, format, args string) bool {
+				detachCommand(p.ctx)
+				return true
+			},
+		},
+		{
+			name: "list", abbrev: "l",
+			summary: "Show the current line in context of the code around it.",
+			details: "Lines with a breakpoint are marked B, b if the breakpoint has a condition or is only for one goroutine, or - if it is disabled.",
+			run: func(p prompt, format, args string) bool {
+				printContext(p.scope.file, p.line, 4)
+				return false
+			},
+		},
+		{
+			name:    "reload",
+			summary: "Read the current file from disk again, so that list shows edits made since the program was built.",
+			details: "The program keeps running the code it was built with.",
+			run: func(p prompt, format, args string) bool {
+				reloadSource(p.scope)
+				return false
+			},
+		},
+		{
+			name: "print", abbrev: "p", formats: true,
+			usage:   "<expression>",
+			summary: "Print a variable or any other Go expression.",
+			details: "Expressions may use Go's operators, as in len(s) > 0, and && and || skip their right side as in Go.\n" +
+				"Expressions may call functions and methods. The call really runs, so it can have side effects.\n" +
+				"Add @-<n> to use the values local variables had <n> pauses ago, or @<n> for the <n>th pause. The last 32 pauses are kept.",
+			run: func(p prompt, format, args string) bool {
+				if args == "" {
+					fmt.Fprintln(output, "usage: print[/format] <expression>")
+				} else if expr, ref, ok := splitPauseRef(args); ok {
+					printPastExpr(expr, ref, format)
+				} else {
+					printExpr(p.scope, args, format)
+				}
+				return false
+			},
+		},
+		{
+			name: "print/flags", abbrev: "p/flags",
+			usage:   "<expression>",
+			summary: "Print an integer as the OR of the named constants in scope.",
+			details: "Bits that no constant accounts for are printed in hex at the end.",
+		},
+		{
+			name: "print/json", abbrev: "p/json",
+			usage:   "<expression>",
+			summary: "Print a value as JSON, the way encoding/json marshals it.",
+			details: "Unexported fields are left out. A value that cannot be marshaled, such as a channel, is printed as usual, followed by why.",
+		},
+		{
+			name: "print/s", abbrev: "p/s",
+			usage:   "<expression>",
+			summary: "Print a []byte or string as a quoted string.",
+			details: "Only the first print-maxbytes bytes are printed. Without /s, print shows a []byte as a string when it is printable UTF-8.",
+		},
+		{
+			name: "print/x", abbrev: "p/x",
+			usage:   "<expression>",
+			summary: "Print a []byte or string as a hex dump, or an integer in hex.",
+			details: "Only the first print-maxbytes bytes are dumped.",
+		},
+		{
+			name:    "inspect",
+			usage:   "<expression>",
+			summary: "Browse a large value as a tree, expanding and collapsing fields with the arrow keys.",
+			details: "Without a terminal, inspect is the same as print.",
+			run: func(p prompt, format, args string) bool {
+				if args == "" {
+					fmt.Fprintln(output, "usage: inspect <expression>")
+				} else {
+					inspectExpr(p.scope, args)
+				}
+				return false
+			},
+		},
+		{
+			name:    "pin",
+			usage:   "<expression>",
+			summary: "Show the value of <expression> at every pause, numbered for unpin.",
+			details: "On a terminal, the pinned values stay on its top lines while the rest scrolls under them. Otherwise they are printed after the line at each pause.",
+			run: func(p prompt, format, args string) bool {
+				pinCommand(p.scope, strings.TrimSpace(args))
+				return false
+			},
+		},
+		{
+			name:    "unpin",
+			usage:   "<n>",
+			summary: "Stop showing pin <n>, as numbered by info pins.",
+			details: "The pins after it are numbered one lower from then on.",
+			run: func(p prompt, format, args string) bool {
+				unpinCommand(p.scope, strings.TrimSpace(args))
+				return false
+			},
+		},
+		{
+			name: "break", abbrev: "b",
+			usage:   "[<file>:]<line> [goroutine <label>] [name <name>] [if <condition>]",
+			summary: "Pause when the program reaches <line> of <file>, or of the current file.",
+			details: "<file> is the path of a generated file, or the end of one, as in server.go:88.\n" +
+				"With goroutine, only the goroutine with that label or id pauses there. It is looked up each time the line is reached, so it may start after the breakpoint is set.\n" +
+				"With name, the breakpoint joins the group of that name, which enable group, disable group and delete group act on together.\n" +
+				"With if, the breakpoint only pauses when <condition> is true. It is evaluated like print each time the line is reached, in the scope of the line. A condition that fails to evaluate pauses.",
+		},
+		{
+			name: "break", abbrev: "b",
+			usage:   "return <function>",
+			summary: "Pause when the named function is about to return.",
+			details: "The function may be named with or without its package name, e.g. \"(*T).String\" or \"main.(*T).String\".",
+			run: func(p prompt, format, args string) bool {
+				breakCommand(p.scope, args)
+				return false
+			},
+		},
+		{
+			name: "break at", abbrev: "b",
+			usage:   "<duration|time>",
+			summary: "Pause the first goroutine to run generated code once the program has run for <duration> or at the wall-clock <time>.",
+			details: "A duration is like 30s or 1m30s. A time is like 15:04:05, for today, or 2006-01-02T15:04:05Z07:00. A new break at replaces the previous one.",
+			run: func(p prompt, format, args string) bool {
+				breakAtCommand(args)
+				return false
+			},
+		},
+		{
+			name: "break chan", abbrev: "b",
+			usage:   "<variable>",
+			summary: "Pause at the next line that is about to send on or receive from the channel in <variable>.",
+			details: "<variable> may be a field of a variable, as in s.jobs. The debugger finds the channel operations in the source of each line it reaches,\n" +
+				"so only operations in generated code on a variable or a field of one are seen. The pause is before the operation, and happens once.",
+			run: func(p prompt, format, args string) bool {
+				breakChanCommand(p.scope, strings.Fields(args))
+				return false
+			},
+		},
+		{
+			name: "break when", abbrev: "b",
+			usage:   "len <variable> > <n> [once]",
+			summary: "Pause when the length of a slice, map or channel grows past <n>, e.g. to catch unbounded growth.",
+			details: "The length is checked like watch len's. It pauses each time the length goes from <n> or less to more than <n>, or with once, only the first time.",
+			run: func(p prompt, format, args string) bool {
+				breakWhenCommand(frameContext(p.ctx, selectedFrame), p.scope, strings.Fields(args))
+				return false
+			},
+		},
+		{
+			name:    "tbreak",
+			usage:   "[<file>:]<line> [goroutine <label>] [name <name>] [if <condition>]",
+			summary: "Set a breakpoint like break <line> that deletes itself the first time it pauses.",
+			details: "A temporary breakpoint whose condition is false, or that is reached by a goroutine it is not for, stays until it does pause.",
+			run: func(p prompt, format, args string) bool {
+				tbreakCommand(p.scope, args)
+				return false
+			},
+		},
+		{
+			name:    "delete",
+			usage:   "<n>...",
+			summary: "Delete the breakpoints with the numbers info breakpoints lists.",
+			details: "Each <n> is a number or a range like 2-4. Breakpoints keep their numbers; deleting one does not renumber the rest.",
+			run: func(p prompt, format, args string) bool {
+				idsCommand("delete", args)
+				return false
+			},
+		},
+		{
+			name:    "enable",
+			usage:   "<n>...",
+			summary: "Enable the breakpoints with the numbers info breakpoints lists, after disable.",
+			run: func(p prompt, format, args string) bool {
+				idsCommand("enable", args)
+				return false
+			},
+		},
+		{
+			name:    "disable",
+			usage:   "<n>...",
+			summary: "Stop the breakpoints with the numbers info breakpoints lists from pausing, without deleting them.",
+			run: func(p prompt, format, args string) bool {
+				idsCommand("disable", args)
+				return false
+			},
+		},
+		{
+			name:    "ignore",
+			usage:   "<n> <count>",
+			summary: "Let breakpoint <n> be reached <count> times without pausing, e.g. to get to a late iteration of a loop.",
+			details: "Only the times the breakpoint would pause count: reaching it with its condition false does not.\n" +
+				"info breakpoints shows how many are left to ignore. A count of 0 makes the breakpoint pause again.",
+			run: func(p prompt, format, args string) bool {
+				ignoreCommand(args)
+				return false
+			},
+		},
+		{
+			name:    "enable group",
+			usage:   "<name>",
+			summary: "Enable the breakpoints named <name> again.",
+			run: func(p prompt, format, args string) bool {
+				groupCommand("enable", args)
+				return false
+			},
+		},
+		{
+			name:    "disable group",
+			usage:   "<name>",
+			summary: "Stop the breakpoints named <name> from pausing, without deleting them.",
+			run: func(p prompt, format, args string) bool {
+				groupCommand("disable", args)
+				return false
+			},
+		},
+		{
+			name:    "delete group",
+			usage:   "<name>",
+			summary: "Delete the breakpoints named <name>.",
+			run: func(p prompt, format, args string) bool {
+				groupCommand("delete", args)
+				return false
+			},
+		},
+		{
+			name:    "watch",
+			usage:   "[len] <variable>",
+			summary: "Pause when the value of a variable changes, or with len, the length of a slice, map or channel.",
+			details: "Values are compared after every assignment and at every line, down to the depth diff compares locals to. A pointer is compared by address, not by what it points to.\n" +
+				"A change made by an assignment in generated code pauses at that assignment; any other, e.g. a send or a change made by code that is not generated, pauses at the line after.\n" +
+				"The change is shown with the old and new values. A watch on a local variable is deleted when the call it belongs to returns.",
+			run: func(p prompt, format, args string) bool {
+				watchCommand(frameContext(p.ctx, selectedFrame), p.scope, strings.Fields(args))
+				return false
+			},
+		},
+		{
+			name:    "delete watch",
+			usage:   "<n>",
+			summary: "Delete watch <n>, as numbered by info watch.",
+			details: "The watches after it are numbered one lower from then on.",
+			run: func(p prompt, format, args string) bool {
+				deleteWatchCommand(strings.TrimSpace(args))
+				return false
+			},
+		},
+		{
+			name:    "catch",
+			usage:   "nil-deref [off]",
+			summary: "Pause when a nil pointer dereference panics, before the panic unwinds.",
+			details: "The pause is at the line that panicked, in the innermost function godebug generated code for, with its locals intact. A dereference in other code pauses in the generated function that called it. Panics in main itself are not caught, because main does not return to godebug.",
+			run: func(p prompt, format, args string) bool {
+				catchCommand(strings.Fields(args))
+				return false
+			},
+		},
+		{
+			name:    "info breakpoints",
+			summary: "Print the breakpoints that are set, with their goroutines and names.",
+			details: "Line breakpoints come first, in the order they were set, with the numbers delete, enable and disable take and how many times each has paused. Then come return breakpoints and channel breakpoints.",
+			run: func(p prompt, format, args string) bool {
+				printBreakpoints()
+				return false
+			},
+		},
+		{
+			name:    "info build",
+			summary: "Print the Go version and platform the program was built with, for bug reports.",
+			details: "When the program was built in module mode, the versions of the main module and of godebug are printed too.",
+			run: func(p prompt, format, args string) bool {
+				printBuildInfo()
+				return false
+			},
+		},
+		{
+			name:    "info calls",
+			summary: "Print how many times each generated function has been called, most called first.",
+			details: "Calls made before the debugger saw the goroutine, and calls to code godebug did not generate, are not counted.",
+			run: func(p prompt, format, args string) bool {
+				printCallCounts()
+				return false
+			},
+		},
+		{
+			name:    "info consts",
+			summary: "Print the constants in scope, from the current block out to the package.",
+			run: func(p prompt, format, args string) bool {
+				printConsts(p.scope)
+				return false
+			},
+		},
+		{
+			name:    "info depth",
+			summary: "Print the call depth of each goroutine running generated code.",
+			details: "The current goroutine is marked with *. Only calls to functions generated by godebug are counted.",
+			run: func(p prompt, format, args string) bool {
+				printDepths()
+				return false
+			},
+		},
+		{
+			name:    "info gls",
+			summary: "Print what godebug keeps in the paused goroutine's goroutine-local storage.",
+			details: "This is for diagnosing godebug itself, e.g. when it does not recognize a goroutine.",
+			run: func(p prompt, format, args string) bool {
+				printGLS(p.ctx)
+				return false
+			},
+		},
+		{
+			name:    "info hits",
+			summary: "Print how many times the current line has run in the current goroutine.",
+			details: "Only lines the debugger has paused at or that have breakpoints are counted, from the first pause or breakpoint there.",
+			run: func(p prompt, format, args string) bool {
+				printHits(p.ctx, p.scope, p.line)
+				return false
+			},
+		},
+		{
+			name:    "info hotspots",
+			usage:   "[count]",
+			summary: "Print the lines the debugger has paused at most often, with how many times, the 10 most paused at unless <count> is given.",
+			details: "Pauses are counted from the start of the session or the last reset hotspots.",
+			run: func(p prompt, format, args string) bool {
+				printHotspots(args)
+				return false
+			},
+		},
+		{
+			name:    "info ignored",
+			summary: "Print the lines ignore line keeps the debugger from pausing at.",
+			run: func(p prompt, format, args string) bool {
+				printIgnored()
+				return false
+			},
+		},
+		{
+			name:    "info locals",
+			summary: "Print the local variables of the current function.",
+			run: func(p prompt, format, args string) bool {
+				printLocals(p.scope)
+				return false
+			},
+		},
+		{
+			name:    "info locals/json",
+			usage:   "[name...]",
+			summary: "Print variables as JSON in the shape of delve's api.Variable.",
+			details: "Without names, print all local variables.",
+			run: func(p prompt, format, args string) bool {
+				printLocalsJSON(p.scope, strings.Fields(args))
+				return false
+			},
+		},
+		{
+			name:    "info pins",
+			summary: "Print the expressions pinned with pin.",
+			run: func(p prompt, format, args string) bool {
+				printPins()
+				return false
+			},
+		},
+		{
+			name:    "info select",
+			aliases: []string{"select-info"},
+			summary: "At a select statement, print which of its cases could proceed now.",
+			details: "Channel expressions that call a function are not checked, so that the call does not run twice.",
+			run: func(p prompt, format, args string) bool {
+				printSelectInfo(p.scope, p.line)
+				return false
+			},
+		},
+		{
+			name:    "info verbose",
+			summary: "Print which kinds of < ... > notices are printed.",
+			details: "Change one kind with \"set verbose <category> on|off\". \"set verbose on\" or \"off\" changes them all again.",
+			run: func(p prompt, format, args string) bool {
+				printVerbose()
+				return false
+			},
+		},
+		{
+			name:    "info watch",
+			summary: "Print the watches set with watch and break when, with the lengths last seen and how many times each has paused the program.",
+			run: func(p prompt, format, args string) bool {
+				printWatches()
+				return false
+			},
+		},
+		{
+			name:    "autolog",
+			usage:   "locals|off",
+			summary: "Print the local variables at every pause, before the prompt.",
+			details: "Values are cut short by print-maxbytes, as with info locals.",
+			run: func(p prompt, format, args string) bool {
+				autologCommand(strings.TrimSpace(args))
+				return false
+			},
+		},
+		{
+			name:    "diff",
+			summary: "Print the local variables that changed since the previous pause.",
+			run: func(p prompt, format, args string) bool {
+				printLocalsDiff(p.scope)
+				return false
+			},
+		},
+		{
+			name:    "mark-scope",
+			summary: "Remember the values of all the local variables, for diff-scope.",
+			run: func(p prompt, format, args string) bool {
+				markScopeCommand(p.ctx, p.scope, p.line)
+				return false
+			},
+		},
+		{
+			name:    "diff-scope",
+			summary: "Print the local variables that were added, removed or changed since mark-scope.",
+			details: "Unlike diff, which compares with the previous pause, diff-scope compares with the mark, so it shows the net effect of running several lines.\n" +
+				"The mark must have been made in the same function call.",
+			run: func(p prompt, format, args string) bool {
+				printScopeDiff(p.ctx, p.scope)
+				return false
+			},
+		},
+		{
+			name:    "mark",
+			usage:   "<expression>",
+			summary: "Remember the value of a number, or the length of a string or collection, for delta.",
+			run: func(p prompt, format, args string) bool {
+				markCommand(p.scope, strings.TrimSpace(args))
+				return false
+			},
+		},
+		{
+			name:    "delta",
+			usage:   "<expression>",
+			summary: "Print how much a marked number or length has changed since mark.",
+			run: func(p prompt, format, args string) bool {
+				deltaCommand(p.scope, strings.TrimSpace(args))
+				return false
+			},
+		},
+		{
+			name:    "equal",
+			usage:   "<a> <b>",
+			summary: "Print whether two expressions are deeply equal, and if not, where they first differ.",
+			details: "The expressions must not contain spaces. Like diff, equal looks at most 10 levels deep.",
+			run: func(p prompt, format, args string) bool {
+				printEqual(p.scope, strings.Fields(args))
+				return false
+			},
+		},
+		{
+			name:    "assert",
+			usage:   "<expression>",
+			summary: "Print a failure if the bool <expression> is false or cannot be evaluated.",
+			details: "When commands do not come from a terminal, a failure also exits the program with status 1, unless godebug.SetAssertHandler installed a handler.",
+			run: func(p prompt, format, args string) bool {
+				assertCommand(p.scope, p.line, args)
+				return false
+			},
+		},
+		{
+			name: "backtrace", abbrev: "bt",
+			usage:   "[count]",
+			summary: "Print the calls the current goroutine is in the middle of, innermost first, or the innermost <count> of them.",
+			details: "Each frame has its function and the line it is at. Only calls to code generated by godebug are included.",
+			run: func(p prompt, format, args string) bool {
+				printBacktrace(p.ctx, args)
+				return false
+			},
+		},
+		{
+			name:    "frame",
+			usage:   "[n]",
+			summary: "Look at call <n> of the backtrace: print, list, info locals and the like use its variables and line. Without <n>, show the call looked at.",
+			details: "The program still resumes from the innermost call. Every pause starts out looking at the innermost call, frame 0.",
+			run: func(p prompt, format, args string) bool {
+				frameCommand(p.ctx, args)
+				return false
+			},
+		},
+		{
+			name:    "up",
+			usage:   "[count]",
+			summary: "Look at the call that called the one looked at, or <count> calls out.",
+			run: func(p prompt, format, args string) bool {
+				moveFrameCommand(p.ctx, "up", 1, args)
+				return false
+			},
+		},
+		{
+			name:    "down",
+			usage:   "[count]",
+			summary: "Look at the call the one looked at made, or <count> calls in.",
+			run: func(p prompt, format, args string) bool {
+				moveFrameCommand(p.ctx, "down", -1, args)
+				return false
+			},
+		},
+		{
+			name:    "backtrace export",
+			usage:   "<file> [locals]",
+			summary: "Write the calls the current goroutine is in the middle of to a file, for a bug report.",
+			details: "Each frame has its function, line and source text. With \"locals\", the frames' local variables are written too.\n" +
+				"Only calls to code generated by godebug are included.",
+			run: func(p prompt, format, args string) bool {
+				exportBacktrace(p.ctx, strings.Fields(args), false)
+				return false
+			},
+		},
+		{
+			name:    "backtrace export/json",
+			usage:   "<file> [locals]",
+			summary: "Like backtrace export, but write JSON.",
+			run: func(p prompt, format, args string) bool {
+				exportBacktrace(p.ctx, strings.Fields(args), true)
+				return false
+			},
+		},
+		{
+			name:    "output",
+			usage:   "[on|off]",
+			summary: "Print what the program wrote to os.Stdout and os.Stderr since the last time. output on starts capturing it, and output off stops.",
+			details: "While the output is captured, it does not mix with what the debugger prints. output off writes what has not been shown to os.Stdout.\n" +
+				"Only writes made through os.Stdout and os.Stderr after output on are captured; println and loggers made earlier, such as the log package's, write as before.",
+			run: func(p prompt, format, args string) bool {
+				outputCommand(args)
+				return false
+			},
+		},
+		{
+			name:    "record",
+			usage:   "<file>|off",
+			summary: "Copy the rest of the session, commands and output, to a file that godebugtest.Replay can check a later run against.",
+			details: "Start at the first pause for a recording that can be replayed. The program's own output is not recorded.",
+			run: func(p prompt, format, args string) bool {
+				recordCommand(p.scope, p.line, strings.TrimSpace(args))
+				return false
+			},
+		},
+		{
+			name:    "reset",
+			summary: "Recompute the debugger's stepping state from the current goroutine's calls, if next or step misbehave.",
+			details: "The debugger follows the current goroutine afterwards. Each value that changed is printed.",
+			run: func(p prompt, format, args string) bool {
+				resetStepping(p.ctx)
+				return false
+			},
+		},
+		{
+			name:    "reset calls",
+			summary: "Set the counts shown by info calls back to zero.",
+			run: func(p prompt, format, args string) bool {
+				resetCallCounts()
+				return false
+			},
+		},
+		{
+			name:    "reset hotspots",
+			summary: "Set the counts shown by info hotspots back to zero.",
+			run: func(p prompt, format, args string) bool {
+				resetHotspots()
+				return false
+			},
+		},
+		{
+			name:    "debug dump",
+			summary: "Print the debugger's own state, to paste into a bug report about godebug.",
+			details: "This includes the stepping state and depth counters, the goroutines, breakpoints and watches, and the settings.",
+			run: func(p prompt, format, args string) bool {
+				printDebugDump()
+				return false
+			},
+		},
+		{
+			name:    "set",
+			usage:   "<setting> <value>",
+			summary: `Change a debugger setting. "set" alone lists the settings.`,
+			run: func(p prompt, format, args string) bool {
+				if isAssignment(args) {
+					assignCommand(p.scope, args)
+				} else {
+					setCommand(args)
+				}
+				return false
+			},
+		},
+		{
+			name:    "set var",
+			usage:   "<variable> = <expression>",
+			summary: "Change the value of a variable of the program, or of a field of one, like p.x. \"var\" may be left out unless the variable has the name of a setting.",
+			details: "The expression is evaluated like print. A number may be of another numeric type than the variable, as long as its value fits exactly.\n" +
+				"Elements of slices, arrays and maps cannot be set this way.",
+			run: func(p prompt, format, args string) bool {
+				assignCommand(p.scope, args)
+				return false
+			},
+		},
+		{
+			name: "quit", abbrev: "q",
+			summary: "Exit the program. Uses os.Exit; deferred functions are not run.",
+			details: "With on-quit set to continue, let the program run on instead. It still pauses at breakpoints.",
+			run: func(p prompt, format, args string) bool {
+				quitSession(false)
+				return true
+			},
+		},
+	}
+}
+
+// spellings returns the ways the command can be typed.
+func (c *command) spellings() []string {
+	s := append([]string{c.name}, c.aliases...)
+	if c.abbrev != "" {
+		rest := ""
+		if i := strings.Index(c.name, " "); i >= 0 {
+			rest = c.name[i:]
+		}
+		s = append(s, c.abbrev+rest)
+	}
+	return s
+}
+
+// findCommand finds the command entered as input and splits off its format
+// and arguments.
+func findCommand(input string) (c *command, format, args string) {
+	var all []spelling
+	for _, c := range commands {
+		if c.run == nil {
+			continue
+		}
+		for _, s := range c.spellings() {
+			all = append(all, spelling{s, c})
+		}
+	}
+	// Try longer spellings first, so that "step goroutine" wins over "step".
+	sort.Stable(byLength(all))
+	for _, sp := range all {
+		if !strings.HasPrefix(input, sp.s) {
+			continue
+		}
+		rest := input[len(sp.s):]
+		format = ""
+		if sp.c.formats && strings.HasPrefix(rest, "/") {
+			format = rest[1:]
+			rest = ""
+			if i := strings.IndexFunc(format, isSpace); i >= 0 {
+				format, rest = format[:i], format[i:]
+			}
+		}
+		if rest != "" && !isSpace(rune(rest[0])) {
+			continue
+		}
+		args = strings.TrimSpace(rest)
+		if sp.c.usage == "" && args != "" {
+			continue
+		}
+		return sp.c, format, args
+	}
+	return nil, "", ""
+}
+
+type spelling struct {
+	s string
+	c *command
+}
+
+type byLength []spelling
+
+func (s byLength) Len() int           { return len(s) }
+func (s byLength) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byLength) Less(i, j int) bool { return len(s[i].s) > len(s[j].s) }
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t'
+}
+
+func (c *command) helpLine() string {
+	line := "    "
+	if c.abbrev != "" {
+		line += "(" + c.abbrev + ") "
+	}
+	line += c.name
+	if c.usage != "" {
+		line += " " + c.usage
+	}
+	return line + ": " + c.summary
+}
+
+func helpText() string {
+	lines := []string{"", "Commands:"}
+	for _, c := range commands {
+		lines = append(lines, c.helpLine())
+	}
+	lines = append(lines,
+		"",
+		"Commands may be given by their full name or by their parenthesized abbreviation.",
+		`Type "help <command>" for more about a command.`,
+		"",
+		"Pressing enter without typing anything repeats the previous command.",
+		"")
+	return strings.Join(lines, "\n")
+}
+
+// printCommandHelp prints the help for every command whose name or
+// abbreviation begins with the given words, e.g. all the info commands
+// for "info".
+func printCommandHelp(name string) {
+	name = strings.Join(strings.Fields(name), " ")
+	found := false
+	for _, c := range commands {
+		for _, s := range c.spellings() {
+			if s == name || strings.HasPrefix(s, name+" ") || strings.HasPrefix(s, name+"/") {
+				fmt.Fprintln(output, c.helpLine())
+				if c.details != "" {
+					fmt.Fprintln(output, "        "+strings.Replace(c.details, "\n", "\n        ", -1))
+				}
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		fmt.Fprintf(output, "There is no command %q. Try \"help\".\n", name)
+	}
+	if name == "set" || strings.HasPrefix(name, "set ") {
+		fmt.Fprintln(output, "Settings:")
+		setCommand("")
+	}
+}
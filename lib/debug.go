@@ -4,11 +4,13 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path"
 	"reflect"
+	"runtime"
 	"strings"
 	"sync/atomic"
 	"unicode"
-	"unsafe"
+	"unicode/utf8"
 
 	"github.com/mailgun/godebug/Godeps/_workspace/src/github.com/0xfaded/eval"
 )
@@ -33,10 +35,18 @@ var (
 	currentDepth     int
 	debuggerDepth    int
 	justLeft         bool // we returned from a function we were stepping through and have not yet run any debug code in the parent function
+	nextCount        int  // lines "next <count>" still has to run before pausing
 	context          = getContextManager()
 	goroutineKey     = 0
 	currentGoroutine uint32
 	ids              idPool
+
+	// lastPause records the frame and line the followed goroutine last paused at.
+	// step uses it to run past further markers on a line it has already stopped at.
+	lastPause struct {
+		ctx  *Context
+		line int
+	}
 )
 
 // EnterFunc marks the beginning of a function. Calling fn should be equivalent to running
@@ -44,6 +54,9 @@ var (
 // fn, and so the caller of EnterFunc should return immediately rather than proceed to
 // duplicate the effects of fn.
 func EnterFunc(fn func()) (ctx *Context, proceed bool) {
+	if isDetached() {
+		return nil, true
+	}
 	// We've entered a new function. If we're in step or next mode we have some bookkeeping to do,
 	// but only if the current goroutine is the one the debugger is following.
 	//
@@ -61,44 +74,73 @@ func EnterFunc(fn func()) (ctx *Context, proceed bool) {
 		//
 		// We record some bookkeeping information with context and then continue running. This means we will
 		// invoke fn, which means the caller should not proceed. After running it, return false.
-		id := uint32(ids.Acquire())
-		defer ids.Release(uint(id))
-		context.SetValues(fn, goroutineKey, id)
+		g := newGoroutine()
+		defer g.release()
+		takeSpawn(g)
+		context.SetValues(fn, goroutineKey, g)
 		return nil, false
 	}
-	if val.(uint32) == atomic.LoadUint32(&currentGoroutine) && currentState != run {
+	g := val.(*goroutine)
+	if g.id == atomic.LoadUint32(&currentGoroutine) && currentState != run {
+		if skipCall(g, callerPC()) {
+			return nil, false
+		}
 		if justLeft {
 			// This means this goroutine ran ExitFunc followed by EnterFunc with no intervening debug calls,
 			// probably because the parent caller is in another package which has not been instrumented.
-			debuggerDepth++
+			// If the parent is generated code instead, it is calling something else on the line we
+			// returned to, as in "return f(x) + f(y)", and next should not stop in that call either.
+			if !calledBy(g.top, 4) {
+				debuggerDepth++
+			}
 			justLeft = false
 		}
 		currentDepth++
 	}
-	return &Context{goroutine: val.(uint32)}, true
+	return g.enter(callerPC()), true
 }
 
 // EnterFuncLit is like EnterFunc, but intended for function literals. The passed callback takes a *Context rather than no input.
 func EnterFuncLit(fn func(*Context)) (ctx *Context, proceed bool) {
+	return enterFuncLit(fn, callerPC())
+}
+
+func enterFuncLit(fn func(*Context), pc uintptr) (ctx *Context, proceed bool) {
+	if isDetached() {
+		return nil, true
+	}
 	val, ok := context.GetValue(goroutineKey)
 	if !ok {
-		id := uint32(ids.Acquire())
-		defer ids.Release(uint(id))
+		g := newGoroutine()
+		defer g.release()
+		takeSpawn(g)
 		context.SetValues(func() {
-			fn(&Context{goroutine: id})
-		}, goroutineKey, id)
+			ctx := g.enter(pc)
+			ctx.body = reflect.ValueOf(fn).Pointer()
+			defer g.exit(ctx)
+			fn(ctx)
+		}, goroutineKey, g)
 		return nil, false
 	}
-	if val.(uint32) == atomic.LoadUint32(&currentGoroutine) && currentState != run {
+	g := val.(*goroutine)
+	if g.id == atomic.LoadUint32(&currentGoroutine) && currentState != run {
+		if skipCall(g, pc) {
+			return nil, false
+		}
 		if justLeft {
 			// This means this goroutine ran ExitFunc followed by EnterFuncLit with no intervening debug calls,
 			// probably because the parent caller is in another package which has not been instrumented.
-			debuggerDepth++
+			// See EnterFunc for when the parent is generated code.
+			if !calledBy(g.top, 5) {
+				debuggerDepth++
+			}
 			justLeft = false
 		}
 		currentDepth++
 	}
-	return &Context{goroutine: val.(uint32)}, true
+	ctx = g.enter(pc)
+	ctx.body = reflect.ValueOf(fn).Pointer()
+	return ctx, true
 }
 
 // EnterFuncWithRecovers is a special wrapper for functions that call recover().
@@ -122,6 +164,7 @@ func EnterFuncWithRecovers(r chan chan interface{}, fn func(*Context)) (<-chan c
 		panicChan = make(chan interface{})
 		ctx       *Context
 		ok        bool
+		pc        = callerPC()
 	)
 	go func() {
 		for {
@@ -143,7 +186,7 @@ func EnterFuncWithRecovers(r chan chan interface{}, fn func(*Context)) (<-chan c
 			}
 			close(panicChan)
 		}()
-		if ctx, ok = EnterFuncLit(fn); ok {
+		if ctx, ok = enterFuncLit(fn, pc); ok {
 			defer ExitFunc(ctx)
 			fn(ctx)
 		}
@@ -152,14 +195,39 @@ func EnterFuncWithRecovers(r chan chan interface{}, fn func(*Context)) (<-chan c
 	return recovers, panicChan
 }
 
-// ExitFunc marks the end of a function.
-func ExitFunc(ctx *Context) {
+// ExitFunc marks the end of a function. results holds a pointer to each of
+// the function's results, which have been set by the time ExitFunc runs.
+func ExitFunc(ctx *Context, results ...interface{}) {
+	if isDetached() {
+		return
+	}
+	if caughtNilDeref(ctx) || breakOnReturn(ctx) {
+		pause(ctx, ctx.scope, ctx.line, "")
+	}
+	if ctx.g.pprofLabeled {
+		unlabelFollowed(ctx)
+	}
+	if atomic.LoadInt32(&watchCount) > 0 {
+		dropFrameWatches(ctx)
+	}
+	ctx.g.exit(ctx)
 	if atomic.LoadUint32(&currentGoroutine) != ctx.goroutine {
 		return
 	}
 	if currentState == run {
 		return
 	}
+	if ctx == finishing {
+		finished(ctx, results)
+		if ctx.parent != nil {
+			currentDepth--
+			pause(ctx.parent, ctx.parent.scope, ctx.parent.line, "")
+			return
+		}
+	}
+	if ctx == until.ctx {
+		leftUntil(ctx)
+	}
 	if currentState == next && currentDepth == debuggerDepth {
 		debuggerDepth--
 		justLeft = true
@@ -170,6 +238,66 @@ func ExitFunc(ctx *Context) {
 // Context contains debugging context information.
 type Context struct {
 	goroutine uint32
+	g         *goroutine
+	parent    *Context // the caller's Context, if the caller is generated code
+	pc        uintptr  // somewhere in the function the Context was created for
+	body      uintptr  // for function literals, the entry of the literal, which runs the lines
+
+	// scope and line are where the function last called Line.
+	scope *Scope
+	line  int
+}
+
+// callerPC returns a program counter in the generated function that called
+// into godebug.
+func callerPC() uintptr {
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // skip runtime.Callers, callerPC and the godebug entrypoint
+	return pcs[0]
+}
+
+// funcName returns the name of the function c was created for, qualified by
+// its package name, e.g. "main.(*T).String".
+func (c *Context) funcName() string {
+	return funcNameForPC(c.pc)
+}
+
+// funcNameForPC returns the package-qualified name of the function that
+// called a godebug entrypoint from pc.
+func funcNameForPC(pc uintptr) string {
+	f := runtime.FuncForPC(pc - 1) // pc is a return address, which may belong to the next function.
+	if f == nil {
+		return ""
+	}
+	name := f.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// calledBy reports whether the function entering generated code was called
+// directly by the code of c's function. skip is the argument to
+// runtime.Callers that finds that caller from calledBy. The generated code of
+// function literals, and of functions with results, runs in closures nested
+// in the function, so calls from those count too.
+func calledBy(c *Context, skip int) bool {
+	if c == nil {
+		return false
+	}
+	var pcs [1]uintptr
+	if runtime.Callers(skip, pcs[:]) == 0 {
+		return false
+	}
+	caller := runtime.FuncForPC(pcs[0] - 1)
+	f := runtime.FuncForPC(c.pc - 1)
+	if c.body != 0 {
+		f = runtime.FuncForPC(c.body)
+	}
+	if caller == nil || f == nil {
+		return false
+	}
+	return caller.Name() == f.Name() || strings.HasPrefix(caller.Name(), f.Name()+".")
 }
 
 type caseSentinel int
@@ -191,22 +319,23 @@ func Comm(c *Context, s *Scope, line int) chan struct{} {
 // EndSelect marks the end of a select statement.
 // It returns a nil channel to read from as the last case of that select statement.
 func EndSelect(c *Context, s *Scope) chan struct{} {
-	if shouldPause(c) {
-		fmt.Println("< All channel expressions evaluated. Choosing case to proceed. >")
+	if !isDetached() && shouldPause(c) {
+		notify("select", "< All channel expressions evaluated. Choosing case to proceed. >")
 	}
 	return nil
 }
 
 // Select marks a select statement.
 func Select(c *Context, s *Scope, line int) {
-	if !shouldPause(c) {
+	if isDetached() {
+		return
+	}
+	if !shouldPause(c) && atomic.LoadInt32(&lineBreakCount) == 0 {
 		return
 	}
 	Line(c, s, line)
-	// Assumes the debugger hasn't switched goroutines. Valid assumption now,
-	// will probably change in the future.
-	if currentState != run {
-		fmt.Println("< Evaluating channel expressions and RHS of send expressions. >")
+	if shouldPause(c) {
+		notify("select", "< Evaluating channel expressions and RHS of send expressions. >")
 	}
 }
 
@@ -220,14 +349,168 @@ func shouldPause(c *Context) bool {
 		(currentState == step || (currentState == next && currentDepth == debuggerDepth))
 }
 
+// resetStepping implements "reset". It recomputes the stepping state from the
+// generated calls on the paused goroutine's stack, follows that goroutine, and
+// says what changed.
+func resetStepping(c *Context) {
+	if activePeek != nil {
+		fmt.Fprintf(output, "Cannot reset while stepping another goroutine; resume to go back to goroutine %s first.\n", goroutineName(activePeek.prev))
+		return
+	}
+	depth := 0
+	for f := c; f != nil; f = f.parent {
+		depth++
+	}
+	changed := false
+	report := func(what string, from, to interface{}) {
+		if from != to {
+			fmt.Fprintf(output, "%s: %v -> %v\n", what, from, to)
+			changed = true
+		}
+	}
+	report("depth", currentDepth, depth)
+	report("debugger depth", debuggerDepth, depth)
+	report("just left a function", justLeft, false)
+	report("skip next else if", skipNextElseIfExpr, false)
+	report("following goroutine", goroutineName(atomic.LoadUint32(&currentGoroutine)), goroutineName(c.goroutine))
+	currentDepth, debuggerDepth = depth, depth
+	justLeft, skipNextElseIfExpr = false, false
+	atomic.StoreUint32(&currentGoroutine, c.goroutine)
+	if !changed {
+		fmt.Fprintf(output, "Nothing to reset; the stepping state matches the %d generated calls on the stack.\n", depth)
+	}
+}
+
 func lineWithPrefix(c *Context, s *Scope, line int, prefix string) {
+	if isDetached() {
+		return
+	}
+	c.scope, c.line = s, line
+	c.g.caught = false
+	if c.g.pprofLabeled {
+		unlabelFollowed(c)
+	}
+	if atomic.LoadInt32(&watchdogLive) == 1 {
+		markActive(c)
+	}
+	countHit(c, s, line)
+	if atomic.LoadInt32(&ignoredCount) > 0 && ignoredLine(s, line) {
+		return
+	}
+	if atomic.LoadInt32(&watchCount) > 0 && checkWatches(c) {
+		pause(c, s, line, prefix)
+		return
+	}
+	if atomic.LoadInt32(&chanBreakCount) > 0 && hitChanBreak(c, s, line) {
+		pause(c, s, line, prefix)
+		return
+	}
+	if atomic.LoadInt32(&envBreaksArmed) == 1 {
+		setEnvBreaks()
+	}
+	if atomic.LoadInt32(&lineBreakCount) > 0 && hitLineBreak(c, s, line) {
+		pause(c, s, line, prefix)
+		return
+	}
+	if atomic.LoadInt32(&timeBreakArmed) == 1 {
+		takeTimeBreak(c)
+	}
+	if atomic.LoadInt32(&startBreakArmed) == 1 {
+		takeStartBreak(c)
+	}
+	if atomic.LoadInt32(&switchArmed) == 1 {
+		takeSwitch(c)
+	}
+	if atomic.LoadInt32(&spawnArmed) == 1 && c.goroutine == atomic.LoadUint32(&spawnFrom) {
+		awaitSpawn()
+	}
+	if reachedUntil(c, line) {
+		pause(c, s, line, prefix)
+		return
+	}
 	if !shouldPause(c) {
 		return
 	}
+	if prefix == "" && currentState == step && c == lastPause.ctx && line == lastPause.line {
+		// A line can be marked more than once, e.g. a loop written on a single line
+		// or an "else if" with a simple statement. Keep stepping until the line changes.
+		debuggerDepth = currentDepth
+		justLeft = false
+		return
+	}
+	if filteredOut(s, line) {
+		// Run on as if the user had paused here and entered the same command.
+		debuggerDepth = currentDepth
+		justLeft = false
+		lastPause.ctx, lastPause.line = c, line
+		return
+	}
+	if currentState == next && nextCount > 0 {
+		// "next <count>" runs this line without pausing, as if the user had
+		// paused here and entered next again.
+		nextCount--
+		debuggerDepth = currentDepth
+		justLeft = false
+		lastPause.ctx, lastPause.line = c, line
+		return
+	}
+	pause(c, s, line, prefix)
+}
+
+// pause shows the user line and waits for commands.
+func pause(c *Context, s *Scope, line int, prefix string) {
+	reason := pauseReason
+	pauseReason = ""
+	if reason != "" && getSetting("dry-run") == "on" {
+		dryRun(c, s, line, reason)
+		return
+	}
+	stopWatchdog()
+	endSkip()
+	finishing = nil
+	until.ctx = nil
 	debuggerDepth = currentDepth
 	justLeft = false
-	fmt.Println("-> " + prefix + strings.TrimSpace(s.fileText[line-1])) // token.Position.Line starts at 1.
-	waitForInput(s, line)
+	lastPause.ctx, lastPause.line = c, line
+	// Any pause, e.g. at a breakpoint, ends a pending "continue switch" or
+	// "next <count>".
+	atomic.StoreInt32(&switchArmed, 0)
+	nextCount = 0
+	trackPausedLine(c, s, line)
+	countPause(s, line)
+	recordLocals(c, s)
+	sendEvent(s, line)
+	labelFollowed(c)
+	if !verbose("defer") {
+		prefix = ""
+	}
+	if pauseHandler == nil {
+		fmt.Fprintln(output, pauseBanner(c, s, line, prefix))
+		notifySends(s, line)
+		if getSetting("autolist") == "on" {
+			printContext(s.file, line, 4)
+		}
+		showPins(s)
+	}
+	if autologLocals {
+		printLocals(s)
+	}
+	waitForInput(c, s, line)
+	if currentState == run {
+		releasePins()
+	}
+	endPeek()
+	startWatchdog()
+}
+
+// pauseBanner returns the line pause prints: the source line after
+// line-prefix or, with banner set to compact, one line that is easy to grep
+// for in a log, like "main.go:42 g1 d3 | x := f()".
+func pauseBanner(c *Context, s *Scope, line int, prefix string) string {
+	if getSetting("banner") == "compact" {
+		return fmt.Sprintf("%s:%d g%d d%d | %s%s", path.Base(s.file.name), line, c.goroutine, atomic.LoadInt32(&c.g.depth), prefix, s.sourceLine(line))
+	}
+	return getSetting("line-prefix") + prefix + s.sourceLine(line)
 }
 
 var skipNextElseIfExpr bool
@@ -242,6 +525,9 @@ func ElseIfSimpleStmt(c *Context, s *Scope, line int) {
 
 // ElseIfExpr marks an "else if" expression.
 func ElseIfExpr(c *Context, s *Scope, line int) {
+	if isDetached() {
+		return
+	}
 	if atomic.LoadUint32(&currentGoroutine) != c.goroutine {
 		return
 	}
@@ -264,37 +550,118 @@ func SetTrace() {
 
 // SetTraceGen is the generated entrypoint to the debugger.
 func SetTraceGen(ctx *Context) {
+	if isDetached() || !isDebuggable(ctx.g) {
+		return
+	}
 	// TODO: The case where the user calls SetTrace multiple times has not been thought out at all yet.
 	if atomic.LoadInt32(&currentState) != run {
+		if atomic.LoadUint32(&currentGoroutine) == ctx.goroutine {
+			nextCount = 0 // a breakpoint ends "next <count>"
+		} else {
+			queueTrace(ctx)
+		}
 		return
 	}
 	atomic.StoreUint32(&currentGoroutine, ctx.goroutine)
+	lastPause.ctx = nil // a breakpoint always pauses, even on the line we last stopped at
+	currentState = step
+	pauseReason = `"breakpoint" statement`
+}
+
+// Break pauses the calling goroutine at the next line of generated code it runs.
+//
+// Where a "breakpoint" statement or a call to SetTrace is turned into a call to
+// SetTraceGen when godebug generates code, Break is an ordinary function. It can
+// be called conditionally, as in
+//
+//	if weird {
+//		godebug.Break()
+//	}
+//
+// and from packages that godebug did not generate code for, as long as the
+// calling goroutine has run generated code before. Otherwise Break does nothing.
+// Like a breakpoint, Break does nothing while the debugger is following
+// another goroutine, or after the user has entered "detach". See also
+// SetDebuggableGoroutines.
+func Break() {
+	if isDetached() {
+		return
+	}
+	val, ok := context.GetValue(goroutineKey)
+	if !ok {
+		return
+	}
+	g := val.(*goroutine)
+	if !isDebuggable(g) {
+		return
+	}
+	id := g.id
+	if atomic.LoadInt32(&currentState) != run && atomic.LoadUint32(&currentGoroutine) != id {
+		return
+	}
+	atomic.StoreUint32(&currentGoroutine, id)
+	lastPause.ctx = nil
 	currentState = step
+	pauseReason = "godebug.Break"
 }
 
-var help = `
-Commands:
-    (h) help: Print this help.
-    (n) next: Run the next line.
-    (s) step: Run for one step.
-    (c) continue: Run until the next breakpoint.
-    (l) list: Show the current line in context of the code around it.
-    (p) print <expression>: Print a variable or any other Go expression.
-    (q) quit: Exit the program. Uses os.Exit; deferred functions are not run.
+// Continue resumes normal execution of the program, as if the user had
+// entered "continue" at the prompt. The debugger pauses again at the next
+// breakpoint.
+func Continue() {
+	atomic.StoreInt32(&currentState, run)
+}
 
-Commands may be given by their full name or by their parenthesized abbreviation.
+// A DebuggerState says how the debugger is running the program.
+type DebuggerState int32
 
-Pressing enter without typing anything repeats the previous command.
-`
+const (
+	// StateRun means the program runs freely until it reaches a breakpoint.
+	StateRun = DebuggerState(run)
+	// StateNext means the debugger pauses at the next line of the function it is in.
+	StateNext = DebuggerState(next)
+	// StateStep means the debugger pauses at the next line of generated code.
+	StateStep = DebuggerState(step)
+)
+
+func (s DebuggerState) String() string {
+	switch s {
+	case StateRun:
+		return "run"
+	case StateNext:
+		return "next"
+	case StateStep:
+		return "step"
+	}
+	return fmt.Sprintf("DebuggerState(%d)", int32(s))
+}
+
+// State returns how the debugger is running the program. It is safe to call
+// from any goroutine at any time, but the state may change as soon as it
+// returns, for example when the user types "continue".
+func State() DebuggerState {
+	return DebuggerState(atomic.LoadInt32(&currentState))
+}
+
+// IsActive reports whether the user is debugging interactively: the debugger
+// is paused, or is stepping through the program and will pause at the next
+// line. It is false while the program runs freely. Programs can use it, for
+// example, to hold back their own chatty logging during a debugging session.
+// Like State, it is safe to call from any goroutine and cheap enough to call
+// often.
+func IsActive() bool {
+	return State() != StateRun
+}
 
 var prevCommand string
 
-func waitForInput(scope *Scope, line int) {
+func waitForInput(ctx *Context, scope *Scope, line int) {
+	selectedFrame = 0
 	for {
-		s, ok := promptUser()
+		p := framePrompt(prompt{ctx, scope, line})
+		s, ok := nextCommand(p.scope, p.line)
 		if !ok {
-			fmt.Println("quitting session")
-			currentState = run
+			quitSession(true)
 			return
 		}
 		s = strings.TrimSpace(s)
@@ -303,68 +670,50 @@ func waitForInput(scope *Scope, line int) {
 		} else {
 			prevCommand = s
 		}
-		switch s {
-		case "":
-		case "?", "h", "help":
-			fmt.Println(help)
-			continue
-		case "n", "next":
-			currentState = next
-			return
-		case "s", "step":
-			currentState = step
-			return
-		case "c", "continue":
-			currentState = run
-			return
-		case "l", "list":
-			printContext(scope.fileText, line, 4)
-			continue
-		case "q", "quit":
-			os.Exit(0)
+		echoed := getSetting("echo") == "on"
+		if echoed {
+			echo(s)
 		}
-		fields := strings.Fields(s)
-		if len(fields) > 0 && (fields[0] == "p" || fields[0] == "print") {
-			if len(fields) > 1 {
-				results, panik, compileErrs := goEval(strings.Join(fields[1:], " "), scope)
-				switch {
-				case compileErrs != nil:
-					for _, err := range compileErrs {
-						fmt.Println(err)
-					}
-				case panik != nil:
-					fmt.Printf("panic (recovered): %v\n", panik)
-				default:
-					s := make([]string, len(results))
-					for i, r := range results {
-						if !r.CanInterface() {
-							if r.CanAddr() {
-								r = reflect.NewAt(r.Type(), unsafe.Pointer(r.UnsafeAddr())).Elem()
-							} else {
-								s[i] = fmt.Sprintf("godebug cannot access this field or method. Sorry! Let us know about it at github.com/mailgun/godebug/issues/new and we'll fix it")
-							}
-						}
-						ifc := r.Interface()
-						if _, ok := ifc.(*eval.ConstNumber); ok {
-							s[i] = fmt.Sprintf("%v", ifc)
-						} else {
-							s[i] = fmt.Sprintf("%#v", ifc)
-						}
-					}
-					fmt.Println(strings.Join(s, ", "))
-				}
-			} else {
-				fmt.Println("usage: print <expression>")
+		c, format, args := findCommand(s)
+		if c == nil || c.name != "record" {
+			// Unless echo already wrote it to output, which is recorded.
+			if !echoed || promptOnOutput && pauseHandler == nil {
+				recordInput(s)
+			}
+		}
+		if c != nil {
+			if c.run(p, format, args) {
+				return
 			}
 			continue
 		}
-		fmt.Println(`Invalid command. Try "help".`)
-		if _, ok := scope.getIdent(strings.TrimSpace(s)); ok {
-			fmt.Printf("If you want to print the variable %s, use the print command.\n", strings.TrimSpace(s))
+		fmt.Fprintln(output, `Invalid command. Try "help".`)
+		if _, ok := p.scope.getIdent(strings.TrimSpace(s)); ok {
+			fmt.Fprintf(output, "If you want to print the variable %s, use the print command.\n", strings.TrimSpace(s))
 		}
 	}
 }
 
+// quitSession ends the session for quit, or if eof is set, at the end of
+// input. It exits the program or lets it run on, as on-quit says.
+func quitSession(eof bool) {
+	exit := !eof
+	switch getSetting("on-quit") {
+	case "continue":
+		exit = false
+	case "exit":
+		exit = true
+	}
+	if eof || !exit {
+		fmt.Fprintln(output, "quitting session")
+	}
+	if exit {
+		releasePins()
+		os.Exit(0)
+	}
+	currentState = run
+}
+
 // goEval runs eval.EvalEnv in a new goroutine. This is a quick hack to
 // keep the debugger from pausing while running eval.EvalEnv.
 // It is also used to recover from panics in the eval library.
@@ -377,7 +726,7 @@ func goEval(expr string, env eval.Env) (result []reflect.Value, panik error, com
 			}
 			close(c)
 		}()
-		result, panik, compileErrors = eval.EvalEnv(expr, env)
+		result, panik, compileErrors = evalEnv(expr, env)
 	}()
 	<-c
 	return
@@ -387,29 +736,61 @@ func dereference(i interface{}) interface{} {
 	return reflect.ValueOf(i).Elem().Interface()
 }
 
-func printContext(lines []string, line, contextCount int) {
+// printContext prints the lines of file around line. Lines with breakpoints
+// are marked in the first column of the prefix, as breakMarks says.
+func printContext(file *sourceFile, line, contextCount int) {
+	lines := file.text()
+	marks := breakMarks(file)
 	line-- // token.Position.Line starts at 1.
-	fmt.Println()
+	fmt.Fprintln(output)
 	for i := line - contextCount; i <= line+contextCount; i++ {
-		prefix := "    "
+		prefix := getSetting("context-marker")
 		if i == line {
-			prefix = "--> "
+			prefix = getSetting("marker")
+		}
+		if m, ok := marks[i+1]; ok {
+			_, size := utf8.DecodeRuneInString(prefix)
+			prefix = string(m) + prefix[size:]
 		}
 		if i >= 0 && i < len(lines) {
 			line := strings.TrimRightFunc(prefix+lines[i], unicode.IsSpace)
-			fmt.Println(line)
+			fmt.Fprintln(output, line)
 		}
 	}
-	fmt.Println()
+	fmt.Fprintln(output)
 }
 
 var input = bufio.NewScanner(os.Stdin)
 
+// promptOnOutput is set when the last prompt was written to output rather than
+// to the terminal.
+var promptOnOutput bool
+
+// echo writes a command entered at the prompt to output, so that a transcript
+// of output shows what was entered as well as the responses.
+func echo(cmd string) {
+	if promptOnOutput && pauseHandler == nil {
+		// Like the prompt, this is not numbered by line-numbers. Commands
+		// from SetInput have been written already.
+		if !echoInput {
+			fmt.Fprintln(destination, cmd)
+		}
+		return
+	}
+	fmt.Fprintln(output, "(godebug) "+cmd)
+}
+
 func fallbackPrompt() (response string, ok bool) {
-	fmt.Print("(godebug) ")
+	promptOnOutput = true
+	// The prompt goes straight to destination so that line-numbers numbers
+	// the line of output that follows instead.
+	fmt.Fprint(destination, "(godebug) ")
 	if !input.Scan() {
 		return "", false
 	}
+	if echoInput {
+		fmt.Fprintln(destination, input.Text())
+	}
 	return input.Text(), true
 }
 
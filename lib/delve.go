@@ -0,0 +1,154 @@
+package godebug
+
+// This file implements "info locals/json", which prints variables in the shape
+// of delve's api.Variable so that front-ends written for delve can read them.
+// Only the fields that make sense for read-only inspection are filled in.
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// The limits delve applies by default when loading variables.
+const (
+	delveMaxRecurse     = 1
+	delveMaxArrayValues = 64
+	delveMaxStringLen   = 64
+)
+
+// delveVariable mirrors the read-only subset of github.com/derekparker/delve/service/api.Variable.
+type delveVariable struct {
+	Name     string          `json:"name"`
+	Type     string          `json:"type"`
+	RealType string          `json:"realType"`
+	Kind     reflect.Kind    `json:"kind"`
+	Value    string          `json:"value"`
+	Len      int64           `json:"len"`
+	Cap      int64           `json:"cap"`
+	Children []delveVariable `json:"children"`
+
+	// Unreadable is set when the value could not be loaded.
+	Unreadable string `json:"unreadable"`
+}
+
+// printLocalsJSON prints the named variables, or all locals if names is empty,
+// as a JSON array of delve variables on a single line.
+func printLocalsJSON(s *Scope, names []string) {
+	values := make(map[string]interface{})
+	if len(names) == 0 {
+		values = s.locals()
+		names = sortedNames(values)
+		for _, name := range names {
+			values[name] = redact(name, values[name])
+		}
+	} else {
+		for _, name := range names {
+			v, ok := s.getIdent(name)
+			if !ok {
+				fmt.Fprintf(output, "%s is not in scope\n", name)
+				return
+			}
+			values[name] = redact(name, v)
+		}
+	}
+	vars := make([]delveVariable, 0, len(names))
+	for _, name := range names {
+		vars = append(vars, newDelveVariable(name, reflect.ValueOf(values[name]), 0))
+	}
+	b, err := json.Marshal(vars)
+	if err != nil {
+		fmt.Fprintln(output, err)
+		return
+	}
+	fmt.Fprintln(output, string(b))
+}
+
+func newDelveVariable(name string, v reflect.Value, depth int) delveVariable {
+	dv := delveVariable{Name: name, Children: []delveVariable{}}
+	if !v.IsValid() {
+		dv.Type, dv.RealType, dv.Value = "interface {}", "interface {}", "nil"
+		dv.Kind = reflect.Interface
+		return dv
+	}
+	t := v.Type()
+	dv.Type, dv.RealType, dv.Kind = t.String(), t.String(), v.Kind()
+	switch v.Kind() {
+	case reflect.Bool:
+		dv.Value = strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		dv.Value = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		dv.Value = strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		dv.Value = strconv.FormatFloat(v.Float(), 'g', -1, t.Bits())
+	case reflect.Complex64, reflect.Complex128:
+		dv.Value = fmt.Sprint(v.Complex())
+		dv.Len = 2
+	case reflect.String:
+		dv.Len = int64(v.Len())
+		dv.Value = v.String()
+		if len(dv.Value) > delveMaxStringLen {
+			dv.Value = dv.Value[:delveMaxStringLen]
+		}
+	case reflect.Slice, reflect.Array:
+		dv.Len = int64(v.Len())
+		dv.Cap = int64(v.Cap())
+		if depth < delveMaxRecurse {
+			for i := 0; i < v.Len() && i < delveMaxArrayValues; i++ {
+				dv.Children = append(dv.Children, newDelveVariable("", v.Index(i), depth+1))
+			}
+		}
+	case reflect.Map:
+		// delve lists a map's keys and values alternately.
+		dv.Len = int64(v.Len())
+		if depth < delveMaxRecurse {
+			keys := v.MapKeys()
+			// Sort the keys so that the output does not change between runs.
+			sort.Sort(byString(keys))
+			for i, k := range keys {
+				if i == delveMaxArrayValues {
+					break
+				}
+				dv.Children = append(dv.Children, newDelveVariable("", k, depth+1), newDelveVariable("", v.MapIndex(k), depth+1))
+			}
+		}
+	case reflect.Struct:
+		dv.Len = int64(v.NumField())
+		if depth < delveMaxRecurse {
+			for i := 0; i < v.NumField(); i++ {
+				dv.Children = append(dv.Children, newDelveVariable(t.Field(i).Name, v.Field(i), depth+1))
+			}
+		}
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			dv.Value = "nil"
+			break
+		}
+		// Like delve, always load the target of a pointer or interface.
+		dv.Children = append(dv.Children, newDelveVariable("", v.Elem(), depth))
+	case reflect.Chan:
+		if v.IsNil() {
+			dv.Value = "nil"
+			break
+		}
+		dv.Len, dv.Cap = int64(v.Len()), int64(v.Cap())
+	case reflect.Func, reflect.UnsafePointer:
+		if v.IsNil() {
+			dv.Value = "nil"
+			break
+		}
+		dv.Value = fmt.Sprintf("%#x", v.Pointer())
+	default:
+		dv.Unreadable = "unsupported kind " + v.Kind().String()
+	}
+	return dv
+}
+
+type byString []reflect.Value
+
+func (v byString) Len() int           { return len(v) }
+func (v byString) Swap(i, j int)      { v[i], v[j] = v[j], v[i] }
+func (v byString) Less(i, j int) bool { return fmt.Sprint(v[i]) < fmt.Sprint(v[j]) }
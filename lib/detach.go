@@ -0,0 +1,31 @@
+package godebug
+
+// This file implements the "detach" command.
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// detached is set by "detach". From then on, the calls generated code makes
+// into godebug return at once, so the program runs close to full speed.
+var detached int32
+
+// isDetached reports whether the user has entered "detach".
+func isDetached() bool {
+	return atomic.LoadInt32(&detached) == 1
+}
+
+// detachCommand implements "detach". It undoes what the session changed in
+// the program, then lets it run on without the debugger.
+func detachCommand(c *Context) {
+	if capturing() {
+		stopCapture()
+	}
+	currentState = run
+	if c.g.pprofLabeled {
+		unlabelFollowed(c)
+	}
+	atomic.StoreInt32(&detached, 1)
+	fmt.Fprintln(output, "Detached. The program runs on without the debugger, which cannot be attached again.")
+}
@@ -0,0 +1,417 @@
+package godebug
+
+// This file implements the "diff", "mark-scope", "diff-scope", "info locals",
+// "info consts" and "equal" commands.
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"unsafe"
+)
+
+// maxCopyDepth bounds how far into nested values copyValue goes. Below that
+// depth, copies share memory with the original.
+const maxCopyDepth = 10
+
+// maxCopyElems and maxCopyBytes bound how much one copyBudget lets copyValue
+// copy, so that a pause with a large slice or map in scope costs no more than
+// one with a small one. Past the budget, as below maxCopyDepth, copies share
+// memory with the original.
+const (
+	maxCopyElems = 10000
+	maxCopyBytes = 256 << 10
+)
+
+// A copyBudget is what is left of the elements and bytes a copy may take.
+type copyBudget struct {
+	elems int
+	bytes uintptr
+}
+
+func newCopyBudget() *copyBudget {
+	return &copyBudget{elems: maxCopyElems, bytes: maxCopyBytes}
+}
+
+// take takes n elements of the given type from the budget, and reports
+// whether there were enough left. If there were not, the budget is left as it
+// was, for smaller values.
+func (b *copyBudget) take(n int, elem reflect.Type) bool {
+	size := uintptr(n) * elem.Size()
+	if n > b.elems || size > b.bytes {
+		return false
+	}
+	b.elems -= n
+	b.bytes -= size
+	return true
+}
+
+type pauseLocals struct {
+	ctx    *Context
+	scope  *Scope
+	n      int // pauses are numbered from 1
+	locals map[string]interface{}
+}
+
+// prevLocals and curLocals hold copies of the locals at the previous and the
+// current pause.
+var prevLocals, curLocals pauseLocals
+
+// pauseHistorySize is how many pauses "print <expression> @<pause>" can look back on.
+const pauseHistorySize = 32
+
+// pauseHistory holds the locals of the last pauseHistorySize pauses, oldest first.
+var pauseHistory []pauseLocals
+
+func recordLocals(c *Context, s *Scope) {
+	prevLocals = curLocals
+	curLocals = pauseLocals{ctx: c, scope: s, n: prevLocals.n + 1, locals: copyLocals(s)}
+	if len(pauseHistory) == pauseHistorySize {
+		pauseHistory = append(pauseHistory[:0], pauseHistory[1:]...)
+	}
+	pauseHistory = append(pauseHistory, curLocals)
+}
+
+// copyLocals copies the locals of s, all of them within one copyBudget.
+func copyLocals(s *Scope) map[string]interface{} {
+	locals := s.locals()
+	b := newCopyBudget()
+	for name, v := range locals {
+		locals[name] = copyInterface(v, b)
+	}
+	return locals
+}
+
+func copyInterface(i interface{}, b *copyBudget) interface{} {
+	if i == nil {
+		return nil
+	}
+	return copyValue(reflect.ValueOf(i), maxCopyDepth, b).Interface()
+}
+
+// copyValue returns a copy of v that does not share the backing memory of
+// slices, maps, arrays or exported struct fields with v, down to the given
+// depth and as far as the budget goes. Pointers, channels and unexported
+// fields are copied shallowly.
+func copyValue(v reflect.Value, depth int, b *copyBudget) reflect.Value {
+	if depth == 0 {
+		return v
+	}
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() || !b.take(v.Len(), v.Type().Elem()) {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(copyValue(v.Index(i), depth-1, b))
+		}
+		return c
+	case reflect.Array:
+		if !b.take(v.Len(), v.Type().Elem()) {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(copyValue(v.Index(i), depth-1, b))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() || !b.take(v.Len(), v.Type().Elem()) {
+			return v
+		}
+		c := reflect.MakeMap(v.Type())
+		for _, k := range v.MapKeys() {
+			c.SetMapIndex(k, copyValue(v.MapIndex(k), depth-1, b))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < c.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(copyValue(v.Field(i), depth-1, b))
+			}
+		}
+		return c
+	}
+	return v
+}
+
+func printLocalsDiff(s *Scope) {
+	switch {
+	case prevLocals.ctx == nil:
+		fmt.Fprintln(output, "There is no previous pause to compare with.")
+		return
+	case prevLocals.ctx != curLocals.ctx:
+		fmt.Fprintln(output, "The previous pause was in a different function call.")
+		return
+	}
+	if !printChangedLocals(prevLocals.locals, s.locals()) {
+		fmt.Fprintln(output, "No locals changed since the previous pause.")
+	}
+}
+
+// printChangedLocals prints the locals that are new in locals or that differ
+// from their values in old, and reports whether there were any.
+func printChangedLocals(old, locals map[string]interface{}) (changed bool) {
+	for _, name := range sortedNames(locals) {
+		v, ok := old[name]
+		switch {
+		case !ok:
+			fmt.Fprintf(output, "%s = %s (new)\n", name, formatValue(redact(name, locals[name])))
+		case !reflect.DeepEqual(v, locals[name]):
+			fmt.Fprintf(output, "%s: %s => %s\n", name, formatValue(redact(name, v)), formatValue(redact(name, locals[name])))
+		default:
+			continue
+		}
+		changed = true
+	}
+	return changed
+}
+
+// markedLocals holds the copy of the locals made by "mark-scope", and
+// markedLine the line it was made at.
+var (
+	markedLocals pauseLocals
+	markedLine   int
+)
+
+// markScopeCommand implements "mark-scope".
+func markScopeCommand(c *Context, s *Scope, line int) {
+	markedLocals = pauseLocals{ctx: c, scope: s, n: curLocals.n, locals: copyLocals(s)}
+	markedLine = line
+	fmt.Fprintf(output, "Marked the locals at line %d. Enter diff-scope to see what has changed since.\n", line)
+}
+
+// printScopeDiff implements "diff-scope". Unlike diff, it also reports the
+// locals that have gone out of scope since the mark.
+func printScopeDiff(c *Context, s *Scope) {
+	switch {
+	case markedLocals.ctx == nil:
+		fmt.Fprintln(output, "No scope is marked. Enter mark-scope first.")
+		return
+	case markedLocals.ctx != c:
+		fmt.Fprintln(output, "The mark was made in a different function call.")
+		return
+	}
+	locals := s.locals()
+	changed := printChangedLocals(markedLocals.locals, locals)
+	for _, name := range sortedNames(markedLocals.locals) {
+		if _, ok := locals[name]; !ok {
+			fmt.Fprintf(output, "%s = %s (gone)\n", name, formatValue(redact(name, markedLocals.locals[name])))
+			changed = true
+		}
+	}
+	if !changed {
+		fmt.Fprintf(output, "No locals changed since the mark at line %d.\n", markedLine)
+	}
+}
+
+func printLocals(s *Scope) {
+	locals := s.locals()
+	if len(locals) == 0 {
+		fmt.Fprintln(output, "No locals.")
+		return
+	}
+	for _, name := range sortedNames(locals) {
+		value := formatValue(redact(name, locals[name]))
+		if ptr, ok := s.getVar(name); ok {
+			if v := reflect.ValueOf(ptr).Elem(); isTypedNil(v) {
+				value = fmt.Sprintf("%s(%s)", v.Type(), value)
+			}
+		}
+		fmt.Fprintf(output, "%s = %s\n", name, value)
+	}
+}
+
+// printConsts implements "info consts".
+func printConsts(s *Scope) {
+	consts := s.consts()
+	if len(consts) == 0 {
+		fmt.Fprintln(output, "No constants.")
+		return
+	}
+	for _, name := range sortedNames(consts) {
+		fmt.Fprintf(output, "%s = %s\n", name, formatValue(redact(name, consts[name])))
+	}
+}
+
+// autologLocals is set by "autolog locals" to print the locals at every
+// pause, as if "info locals" had been entered.
+var autologLocals bool
+
+func autologCommand(args string) {
+	switch args {
+	case "locals":
+		autologLocals = true
+		fmt.Fprintln(output, "Will print the local variables at every pause.")
+	case "off":
+		autologLocals = false
+		fmt.Fprintln(output, "No longer printing the local variables at every pause.")
+	default:
+		fmt.Fprintln(output, "usage: autolog locals|off")
+	}
+}
+
+func sortedNames(m map[string]interface{}) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// printEqual implements "equal <a> <b>".
+func printEqual(scope *Scope, args []string) {
+	if len(args) != 2 {
+		fmt.Fprintln(output, "usage: equal <a> <b>")
+		return
+	}
+	a, ok := evalValue(scope, args[0])
+	if !ok {
+		return
+	}
+	b, ok := evalValue(scope, args[1])
+	if !ok {
+		return
+	}
+	pa, pb, why := firstDifference(a, b, args[0], args[1], maxCopyDepth)
+	if why == "" {
+		fmt.Fprintf(output, "%s and %s are equal.\n", args[0], args[1])
+		return
+	}
+	fmt.Fprintf(output, "%s and %s differ: %s\n", args[0], args[1], why)
+	if pa != args[0] {
+		fmt.Fprintf(output, "    at %s and %s\n", pa, pb)
+	}
+}
+
+// firstDifference finds the first place where a and b, reached through the
+// expressions pa and pb, are not deeply equal. It returns the expressions for
+// that place and what differs there, or an empty why if a and b are equal.
+// Below the given depth, values are compared with reflect.DeepEqual.
+func firstDifference(a, b reflect.Value, pa, pb string, depth int) (da, db, why string) {
+	switch {
+	case !a.IsValid() || !b.IsValid():
+		if a.IsValid() != b.IsValid() {
+			return pa, pb, fmt.Sprintf("%s vs %s", valueString(pa, a), valueString(pb, b))
+		}
+		return "", "", ""
+	case a.Type() != b.Type():
+		return pa, pb, fmt.Sprintf("type %s vs %s", a.Type(), b.Type())
+	case depth == 0:
+		if a.CanInterface() && b.CanInterface() && !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			return pa, pb, "values differ below the depth limit"
+		}
+		return "", "", ""
+	}
+	switch a.Kind() {
+	case reflect.Slice, reflect.Array:
+		if a.Kind() == reflect.Slice && a.IsNil() != b.IsNil() {
+			return pa, pb, fmt.Sprintf("%s vs %s", valueString(pa, a), valueString(pb, b))
+		}
+		if a.Len() != b.Len() {
+			return pa, pb, fmt.Sprintf("len %d vs %d", a.Len(), b.Len())
+		}
+		for i := 0; i < a.Len(); i++ {
+			index := fmt.Sprintf("[%d]", i)
+			if da, db, why = firstDifference(a.Index(i), b.Index(i), pa+index, pb+index, depth-1); why != "" {
+				return
+			}
+		}
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() {
+			return pa, pb, fmt.Sprintf("%s vs %s", valueString(pa, a), valueString(pb, b))
+		}
+		if a.Len() != b.Len() {
+			return pa, pb, fmt.Sprintf("len %d vs %d", a.Len(), b.Len())
+		}
+		keys := a.MapKeys()
+		sort.Sort(byString(keys))
+		for _, k := range keys {
+			index := fmt.Sprintf("[%s]", valueString("", k))
+			bv := b.MapIndex(k)
+			if !bv.IsValid() {
+				return pa + index, pb + index, "key only in " + pa
+			}
+			if da, db, why = firstDifference(a.MapIndex(k), bv, pa+index, pb+index, depth-1); why != "" {
+				return
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			field := "." + a.Type().Field(i).Name
+			if da, db, why = firstDifference(a.Field(i), b.Field(i), pa+field, pb+field, depth-1); why != "" {
+				return
+			}
+		}
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				return pa, pb, fmt.Sprintf("%s vs %s", valueString(pa, a), valueString(pb, b))
+			}
+			return "", "", ""
+		}
+		if a.Kind() == reflect.Ptr && a.Pointer() == b.Pointer() {
+			return "", "", ""
+		}
+		return firstDifference(a.Elem(), b.Elem(), pa, pb, depth-1)
+	case reflect.Func:
+		// reflect.DeepEqual only considers nil funcs equal.
+		if !a.IsNil() || !b.IsNil() {
+			return pa, pb, "funcs are only equal if both are nil"
+		}
+	case reflect.Chan, reflect.UnsafePointer:
+		if a.Pointer() != b.Pointer() {
+			return pa, pb, fmt.Sprintf("%s vs %s", valueString(pa, a), valueString(pb, b))
+		}
+	default:
+		if !leafEqual(a, b) {
+			return pa, pb, fmt.Sprintf("%s vs %s", valueString(pa, a), valueString(pb, b))
+		}
+	}
+	return "", "", ""
+}
+
+// leafEqual compares two values of the same basic type the way
+// reflect.DeepEqual does. Unlike comparing their printed forms, it is not
+// fooled by print-maxbytes or a redactor.
+func leafEqual(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float()
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == b.Complex()
+	case reflect.String:
+		return a.String() == b.String()
+	}
+	return fmt.Sprintf("%#v", a) == fmt.Sprintf("%#v", b)
+}
+
+// valueString formats v, which was reached through the named expression and
+// may not be usable with Interface because it was reached through an
+// unexported field.
+func valueString(name string, v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	if !v.CanInterface() && v.CanAddr() {
+		v = reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+	}
+	if v.CanInterface() {
+		return formatValue(redact(name, v.Interface()))
+	}
+	if redactor != nil {
+		// The redactor cannot be given a value that is not an interface.
+		return Redacted
+	}
+	return fmt.Sprintf("%#v", v)
+}
@@ -0,0 +1,23 @@
+package godebug
+
+// This file implements the dry-run setting, which reports where breakpoints
+// would pause the program instead of pausing it.
+
+import (
+	"fmt"
+	"path"
+)
+
+// pauseReason names what is about to pause a program that was running
+// freely, such as "breakpoint 2" or "break chan jobs". Each kind of breakpoint
+// sets it as it makes the debugger follow its goroutine, and pause clears it.
+// Pauses the user asked for with step or next have no reason.
+var pauseReason string
+
+// dryRun is called by pause instead of pausing when dry-run is on and the
+// program was running freely. It says where the program would have paused
+// and why, and lets it run on.
+func dryRun(c *Context, s *Scope, line int, reason string) {
+	fmt.Fprintf(output, "dry run: would pause at %s:%d in %s, for %s\n", path.Base(s.file.name), line, c.funcName(), reason)
+	currentState = run
+}
@@ -0,0 +1,108 @@
+package godebug
+
+// This file implements "debug dump", which prints the debugger's own state
+// for bug reports.
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// printDebugDump implements "debug dump". It only reads state, so it is safe
+// to run at any pause.
+func printDebugDump() {
+	fmt.Fprintf(output, "state: %s\n", State())
+	fmt.Fprintf(output, "following goroutine: %s\n", goroutineName(atomic.LoadUint32(&currentGoroutine)))
+	fmt.Fprintf(output, "depth: current %d, debugger %d, just left %t\n", currentDepth, debuggerDepth, justLeft)
+	if lastPause.ctx != nil {
+		fmt.Fprintf(output, "last pause: %s, line %d\n", lastPause.ctx.funcName(), lastPause.line)
+	}
+	if activePeek != nil {
+		fmt.Fprintf(output, "stepping goroutine, back to goroutine %d\n", activePeek.prev)
+	}
+	if atomic.LoadInt32(&switchArmed) == 1 {
+		fmt.Fprintf(output, "continue switch: waiting for a goroutine other than %d\n", atomic.LoadUint32(&switchFrom))
+	}
+	if atomic.LoadInt32(&exitArmed) == 1 {
+		fmt.Fprintf(output, "wait goroutine: waiting for goroutine %d to exit\n", atomic.LoadUint32(&exitID))
+	}
+	if capturing() {
+		fmt.Fprintln(output, "output: capturing the program's output")
+	}
+	if autologLocals {
+		fmt.Fprintln(output, "autolog: locals")
+	}
+
+	fmt.Fprintln(output, "goroutines:")
+	printDepths()
+
+	fmt.Fprintln(output, "breakpoints:")
+	n := 0
+	for _, desc := range breakpointDescs() {
+		fmt.Fprintf(output, "  %s\n", desc)
+		n++
+	}
+	timeBreak.Lock()
+	if timeBreak.timer != nil {
+		fmt.Fprintf(output, "  break %s\n", timeBreak.desc)
+		n++
+	}
+	timeBreak.Unlock()
+	if atomic.LoadInt32(&timeBreakArmed) == 1 {
+		fmt.Fprintln(output, "  break at: time reached, waiting for a line")
+		n++
+	}
+	if atomic.LoadInt32(&startBreakArmed) == 1 {
+		fmt.Fprintln(output, "  break at start: waiting for a line")
+		n++
+	}
+	if atomic.LoadInt32(&catchNilDeref) == 1 {
+		fmt.Fprintln(output, "  catch nil-deref")
+		n++
+	}
+	if n == 0 {
+		fmt.Fprintln(output, "  none")
+	}
+
+	fmt.Fprintln(output, "watches:")
+	ws := loadWatches()
+	for _, w := range ws {
+		fmt.Fprintf(output, "  %s\n", w)
+	}
+	if len(ws) == 0 {
+		fmt.Fprintln(output, "  none")
+	}
+
+	fmt.Fprintln(output, "settings changed from their defaults:")
+	n = 0
+	for _, name := range sortedSettings() {
+		if v := getSetting(name); v != defaultSettings[name] {
+			fmt.Fprintf(output, "  %s = %q\n", name, v)
+			n++
+		}
+	}
+	settingsMu.RLock()
+	for _, name := range sortedCategories() {
+		if on, ok := verboseOverrides[name]; ok {
+			state := "off"
+			if on {
+				state = "on"
+			}
+			fmt.Fprintf(output, "  verbose %s = %s\n", name, state)
+			n++
+		}
+	}
+	settingsMu.RUnlock()
+	if n == 0 {
+		fmt.Fprintln(output, "  none")
+	}
+}
+
+// defaultSettings holds the values the settings start with.
+var defaultSettings = func() map[string]string {
+	m := make(map[string]string, len(settings))
+	for name, s := range settings {
+		m[name] = s.value
+	}
+	return m
+}()
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/mailgun/godebug/Godeps/_workspace/src/github.com/0xfaded/eval"
 )
@@ -15,19 +16,158 @@ import (
 type Scope struct {
 	Vars, Consts, Funcs map[string]interface{}
 	parent              *Scope
-	fileText            []string
+	file                *sourceFile
+	isFile              bool
+}
+
+// A sourceFile holds the text of a file. All scopes in the file share it, so
+// that reload changes the text for all of them.
+type sourceFile struct {
+	name     string   // the path the file was generated from, if known
+	embedded []string // the text embedded in the generated code
+
+	mu    sync.Mutex
+	lines []string // the text shown, from embedded, the source provider or reload
+	gen   int      // sourceProviderGen when lines was last set
+}
+
+// sourceProvider, if set by SetSourceProvider, supplies the text of files in
+// place of the text embedded in generated code. sourceProviderGen counts the
+// calls to SetSourceProvider, so that files ask the new provider for their text.
+var (
+	sourceProviderMu  sync.Mutex
+	sourceProvider    func(file string) ([]string, error)
+	sourceProviderGen int
+)
+
+// SetSourceProvider makes the debugger show source text returned by f, e.g.
+// read from an embed.FS or a version control system, instead of the text
+// embedded in the generated code. f is given the path of the file as it was
+// when godebug generated code for it, and returns the file's lines. If f
+// returns an error, the embedded text is shown. Passing nil shows the
+// embedded text again.
+func SetSourceProvider(f func(file string) ([]string, error)) {
+	sourceProviderMu.Lock()
+	sourceProvider = f
+	sourceProviderGen++
+	sourceProviderMu.Unlock()
+}
+
+// text returns the lines of f to show, asking the source provider for them if
+// it has changed since they were last set.
+func (f *sourceFile) text() []string {
+	sourceProviderMu.Lock()
+	provider, gen := sourceProvider, sourceProviderGen
+	sourceProviderMu.Unlock()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.gen != gen {
+		f.gen = gen
+		f.lines = f.embedded
+		if provider != nil && f.name != "" {
+			if lines, err := provider(f.name); err == nil {
+				f.lines = lines
+			}
+		}
+	}
+	return f.lines
+}
+
+// setText replaces the lines of f to show, as reload does.
+func (f *sourceFile) setText(lines []string) {
+	f.text() // so that a provider set earlier does not replace lines later
+	f.mu.Lock()
+	f.lines = lines
+	f.mu.Unlock()
 }
 
 // EnteringNewFile returns a new Scope and internally sets
 // the current scope to be the returned scope.
+//
+// Code generated by older versions of godebug calls EnteringNewFile;
+// current versions call EnteringNewFileAt.
 func EnteringNewFile(parent *Scope, fileText string) *Scope {
+	return EnteringNewFileAt(parent, "", fileText)
+}
+
+// EnteringNewFileAt is like EnteringNewFile, but also records the path of
+// the file the code was generated from, so that the reload command can read
+// it again.
+func EnteringNewFileAt(parent *Scope, filename, fileText string) *Scope {
 	return &Scope{
-		Vars:     make(map[string]interface{}),
-		Consts:   make(map[string]interface{}),
-		Funcs:    make(map[string]interface{}),
-		parent:   parent,
-		fileText: parseLines(fileText),
+		Vars:   make(map[string]interface{}),
+		Consts: make(map[string]interface{}),
+		Funcs:  make(map[string]interface{}),
+		parent: parent,
+		file:   newSourceFile(filename, fileText),
+		isFile: true,
+	}
+}
+
+func newSourceFile(filename, fileText string) *sourceFile {
+	lines := parseLines(fileText)
+	f := &sourceFile{name: filename, embedded: lines, lines: lines}
+	sourceFiles.Lock()
+	sourceFiles.list = append(sourceFiles.list, f)
+	sourceFiles.Unlock()
+	return f
+}
+
+// sourceFiles lists the files of generated code, in the order their package
+// variables were initialized.
+var sourceFiles struct {
+	sync.Mutex
+	list []*sourceFile
+}
+
+// findSourceFile returns the file of generated code whose path is name or ends
+// in /name. If no file, or more than one, matches, it prints so and ok is false.
+func findSourceFile(name string) (f *sourceFile, ok bool) {
+	found := matchSourceFiles(name)
+	switch len(found) {
+	case 0:
+		fmt.Fprintf(output, "There is no generated file named %s.\n", name)
+		return nil, false
+	case 1:
+		return found[0], true
+	}
+	fmt.Fprintf(output, "%s could be any of %s.\n", name, sourceFileNames(found))
+	return nil, false
+}
+
+// lookupSourceFile is like findSourceFile, but returns an error instead of
+// printing it.
+func lookupSourceFile(name string) (*sourceFile, error) {
+	found := matchSourceFiles(name)
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("godebug: there is no generated file named %s", name)
+	case 1:
+		return found[0], nil
+	}
+	return nil, fmt.Errorf("godebug: %s could be any of %s", name, sourceFileNames(found))
+}
+
+// matchSourceFiles returns the files of generated code whose path is name or
+// ends in /name.
+func matchSourceFiles(name string) []*sourceFile {
+	sourceFiles.Lock()
+	defer sourceFiles.Unlock()
+	var found []*sourceFile
+	for _, sf := range sourceFiles.list {
+		if sf.name == name || strings.HasSuffix(sf.name, "/"+name) {
+			found = append(found, sf)
+		}
 	}
+	return found
+}
+
+func sourceFileNames(files []*sourceFile) string {
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = f.name
+	}
+	return strings.Join(names, ", ")
 }
 
 func parseLines(text string) []string {
@@ -46,12 +186,22 @@ func parseLines(text string) []string {
 // the returned scope.
 func (s *Scope) EnteringNewChildScope() *Scope {
 	return &Scope{
-		Vars:     make(map[string]interface{}),
-		Consts:   make(map[string]interface{}),
-		Funcs:    make(map[string]interface{}),
-		parent:   s,
-		fileText: s.fileText,
+		Vars:   make(map[string]interface{}),
+		Consts: make(map[string]interface{}),
+		Funcs:  make(map[string]interface{}),
+		parent: s,
+		file:   s.file,
+	}
+}
+
+// sourceLine returns the text of the given line of s's file with surrounding
+// whitespace removed. Lines are numbered from 1, like token.Position.Line.
+func (s *Scope) sourceLine(line int) string {
+	lines := s.file.text()
+	if line < 1 || line > len(lines) {
+		return fmt.Sprintf("<line %d is past the end of the file>", line)
 	}
+	return strings.TrimSpace(lines[line-1])
 }
 
 func (s *Scope) getIdent(name string) (i interface{}, ok bool) {
@@ -70,6 +220,58 @@ func (s *Scope) getIdent(name string) (i interface{}, ok bool) {
 	return nil, false
 }
 
+// getVar returns a pointer to the variable with the given name. ok is false if
+// name does not refer to a variable.
+func (s *Scope) getVar(name string) (ptr interface{}, ok bool) {
+	for scope := s; scope != nil; scope = scope.parent {
+		if ptr, ok = scope.Vars[name]; ok {
+			return ptr, true
+		}
+		if _, ok = scope.Consts[name]; ok {
+			return nil, false
+		}
+		if _, ok = scope.Funcs[name]; ok {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// locals returns the current values of the variables declared between s and
+// the enclosing file scope. Inner declarations shadow outer ones.
+func (s *Scope) locals() map[string]interface{} {
+	vars := make(map[string]interface{})
+	for scope := s; scope != nil && !scope.isFile; scope = scope.parent {
+		for name, v := range scope.Vars {
+			if _, ok := vars[name]; !ok {
+				vars[name] = dereference(v)
+			}
+		}
+	}
+	return vars
+}
+
+// consts returns the constants in scope at s, up to and including the
+// package scope. As in getIdent, inner declarations of any kind shadow outer
+// ones.
+func (s *Scope) consts() map[string]interface{} {
+	seen := make(map[string]bool)
+	consts := make(map[string]interface{})
+	for scope := s; scope != nil; scope = scope.parent {
+		for name, v := range scope.Consts {
+			if !seen[name] {
+				consts[name] = v
+			}
+		}
+		for _, m := range []map[string]interface{}{scope.Vars, scope.Consts, scope.Funcs} {
+			for name := range m {
+				seen[name] = true
+			}
+		}
+	}
+	return consts
+}
+
 // Declare creates new variable bindings in s from a list of name, value pairs.
 // The values should be pointers to the values in the program rather than copies
 // of them so that s can track changes to them.
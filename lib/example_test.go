@@ -0,0 +1,283 @@
+package godebug_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"runtime/pprof"
+	"strings"
+
+	"github.com/mailgun/godebug/lib"
+)
+
+func ExampleScope_Eval() {
+	godebug.SetPauseHandler(func(s godebug.Snapshot) string {
+		for _, expr := range []string{
+			"p",
+			"p.X",
+			"p.y",
+			"sides[2]",
+			`names["x"]`,
+			"len(sides) + cap(sides)",
+			"p.X * p.y",
+			"sides[5]",
+			"q",
+		} {
+			v, err := s.Scope.Eval(expr)
+			if err != nil {
+				fmt.Printf("%s: %v\n", expr, err)
+				continue
+			}
+			fmt.Printf("%s = %v\n", expr, v)
+		}
+		return "continue"
+	})
+	defer godebug.SetPauseHandler(nil)
+	shapes()
+	// Output:
+	// p = {3 4}
+	// p.X = 3
+	// p.y = 4
+	// sides[2] = 5
+	// names["x"] = 1
+	// len(sides) + cap(sides) = 6
+	// p.X * p.y = 12
+	// sides[5]: panic (recovered): runtime error: index out of range [5] with length 3
+	// q: undefined: q
+}
+
+func ExampleSetAssertHandler() {
+	godebug.SetAssertHandler(func(expr string, err error, s godebug.Snapshot) {
+		fmt.Printf("%q failed at line %d (error: %v)\n", expr, s.Line, err)
+	})
+	defer godebug.SetAssertHandler(nil)
+	commands := []string{"assert p.X == 3", "assert p.y > 10", "assert len(sides)", "continue"}
+	godebug.SetPauseHandler(func(s godebug.Snapshot) string {
+		cmd := commands[0]
+		commands = commands[1:]
+		return cmd
+	})
+	defer godebug.SetPauseHandler(nil)
+	godebug.SetOutput(os.Stdout)
+	shapes()
+	// Output:
+	// *** assertion failed at line 9: p.y > 10
+	// "p.y > 10" failed at line 9 (error: <nil>)
+	// *** assertion failed at line 9: len(sides): len(sides) has type int, not bool
+	// "len(sides)" failed at line 9 (error: len(sides) has type int, not bool)
+}
+
+type point struct{ X, y int }
+
+// shapes is the code 'godebug test' generates for this function:
+//
+//	func shapes() {
+//		p := point{X: 3, y: 4}
+//		sides := []int{3, 4, 5}
+//		names := map[string]int{"x": 1}
+//		_ = "breakpoint"
+//	}
+func shapes() {
+	ctx, ok := godebug.EnterFunc(shapes)
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := example_test_go_scope.EnteringNewChildScope()
+	godebug.Line(ctx, scope, 6)
+	p := point{X: 3, y: 4}
+	scope.Declare("p", &p)
+	godebug.Line(ctx, scope, 7)
+	sides := []int{3, 4, 5}
+	scope.Declare("sides", &sides)
+	godebug.Line(ctx, scope, 8)
+	names := map[string]int{"x": 1}
+	scope.Declare("names", &names)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 9)
+}
+
+var example_test_go_scope = godebug.EnteringNewFile(nil, example_test_go_contents)
+
+var example_test_go_contents = `package main
+
+type point struct{ X, y int }
+
+func shapes() {
+	p := point{X: 3, y: 4}
+	sides := []int{3, 4, 5}
+	names := map[string]int{"x": 1}
+	_ = "breakpoint"
+}
+`
+
+func Example_captureOutput() {
+	godebug.SetOutput(os.Stdout)
+	commands := []string{"output", "output on", "next", "next", "next", "output", "output", "output off", "continue"}
+	godebug.SetPauseHandler(func(s godebug.Snapshot) string {
+		cmd := commands[0]
+		commands = commands[1:]
+		return cmd
+	})
+	defer godebug.SetPauseHandler(nil)
+	chatty()
+	// Output:
+	// The program's output is not being captured. Enter output on to capture it.
+	// Capturing the program's output. Enter output to see it.
+	// working...
+	// warning: almost done
+	// No new output from the program.
+	// Stopped capturing the program's output.
+	// done
+}
+
+func Example_pprofLabels() {
+	labeled := func(label string) bool {
+		var b bytes.Buffer
+		pprof.Lookup("goroutine").WriteTo(&b, 1)
+		return strings.Contains(b.String(), label)
+	}
+	commands := []string{"set pprof-labels on", "next", "continue"}
+	godebug.SetPauseHandler(func(s godebug.Snapshot) string {
+		fmt.Printf("paused at line %d, labeled: %t\n", s.Line, labeled(`"godebug":"followed"`))
+		cmd := commands[0]
+		commands = commands[1:]
+		return cmd
+	})
+	defer godebug.SetPauseHandler(nil)
+	pprof.Do(context.Background(), pprof.Labels("job", "chatty"), func(context.Context) {
+		chatty()
+		fmt.Printf("after, labeled: %t, own label back: %t\n", labeled(`"godebug":"followed"`), labeled(`"job":"chatty"`))
+	})
+	// Output:
+	// paused at line 9, labeled: false
+	// paused at line 9, labeled: false
+	// paused at line 10, labeled: true
+	// working...
+	// done
+	// after, labeled: false, own label back: true
+}
+
+// chatty is the code 'godebug test' generates for this function:
+//
+//	func chatty() {
+//		_ = "breakpoint"
+//		fmt.Println("working...")
+//		fmt.Fprintln(os.Stderr, "warning: almost done")
+//		fmt.Println("done")
+//	}
+func chatty() {
+	ctx, ok := godebug.EnterFunc(chatty)
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, chatty_scope, 9)
+	godebug.Line(ctx, chatty_scope, 10)
+	fmt.Println("working...")
+	godebug.Line(ctx, chatty_scope, 11)
+	fmt.Fprintln(os.Stderr, "warning: almost done")
+	godebug.Line(ctx, chatty_scope, 12)
+	fmt.Println("done")
+}
+
+var chatty_scope = godebug.EnteringNewFile(nil, chatty_contents).EnteringNewChildScope()
+
+var chatty_contents = `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func chatty() {
+	_ = "breakpoint"
+	fmt.Println("working...")
+	fmt.Fprintln(os.Stderr, "warning: almost done")
+	fmt.Println("done")
+}
+`
+
+func ExampleAddBreakpoint() {
+	godebug.SetPauseHandler(func(s godebug.Snapshot) string {
+		fmt.Printf("paused at line %d: i = %v, total = %v\n", s.Line, s.Locals["i"], s.Locals["total"])
+		return "continue"
+	})
+	defer godebug.SetPauseHandler(nil)
+
+	if _, err := godebug.AddBreakpoint("tally.go", 40); err != nil {
+		fmt.Println(err)
+	}
+	if _, err := godebug.AddBreakpoint("missing.go", 6); err != nil {
+		fmt.Println(err)
+	}
+	other, _ := godebug.AddBreakpoint("tally.go", 8, godebug.BreakGoroutine("worker"))
+	id, err := godebug.AddBreakpoint("tally.go", 6)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("tally:", tally())
+	godebug.RemoveBreakpoint(id)
+	godebug.RemoveBreakpoint(other)
+	fmt.Println("tally again:", tally())
+	// Output:
+	// godebug: example/tally.go has no line 40; it has 9 lines
+	// godebug: there is no generated file named missing.go
+	// paused at line 6: i = 1, total = 0
+	// paused at line 6: i = 2, total = 1
+	// paused at line 6: i = 3, total = 3
+	// tally: 6
+	// tally again: 6
+}
+
+// tally is the code 'godebug test' generates for this function:
+//
+//	func tally() int {
+//		total := 0
+//		for i := 1; i <= 3; i++ {
+//			total += i
+//		}
+//		return total
+//	}
+func tally() int {
+	var result1 int
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = tally()
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx)
+	godebug.Line(ctx, tally_go_scope, 4)
+	total := 0
+	scope := tally_go_scope.EnteringNewChildScope()
+	scope.Declare("total", &total)
+	{
+		scope := scope.EnteringNewChildScope()
+		for i := 1; i <= 3; i++ {
+			godebug.Line(ctx, scope, 5)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 6)
+			total += i
+		}
+		godebug.Line(ctx, scope, 5)
+	}
+	godebug.Line(ctx, scope, 8)
+	return total
+}
+
+var tally_go_scope = godebug.EnteringNewFileAt(nil, "example/tally.go", tally_go_contents)
+
+var tally_go_contents = `package main
+
+func tally() int {
+	total := 0
+	for i := 1; i <= 3; i++ {
+		total += i
+	}
+	return total
+}
+`
@@ -0,0 +1,47 @@
+package godebug
+
+// This file implements the "finish" command.
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// finishing is the frame "finish" was entered in, until it returns or the
+// program pauses somewhere else first. Like skipping, it is only touched by
+// the goroutine the debugger follows.
+var finishing *Context
+
+// finishCommand implements "finish". The program runs without pausing in the
+// current function or the calls it makes, and pauses in the caller, at the
+// line that made the call.
+func finishCommand(c *Context) bool {
+	if c.parent == nil && c.funcName() == "main.main" {
+		fmt.Fprintln(output, "main.main does not return to a caller; use continue to run the program to the end.")
+		return false
+	}
+	finishing = c
+	currentState = next
+	debuggerDepth = currentDepth - 1
+	return true
+}
+
+// finished is called by ExitFunc for the frame "finish" was entered in, which
+// then pauses at the caller's line. It says what the function returned, and
+// if the caller is not generated code, makes sure the program pauses at the
+// next line it runs, as "next" does at the end of a function. results holds a
+// pointer to each result.
+func finished(c *Context, results []interface{}) {
+	finishing = nil
+	justLeft = true
+	if len(results) == 0 {
+		fmt.Fprintf(output, "< %s() returned >\n", c.funcName())
+		return
+	}
+	values := make([]string, len(results))
+	for i, r := range results {
+		values[i] = valueString("", reflect.ValueOf(r).Elem())
+	}
+	fmt.Fprintf(output, "< %s() returned %s >\n", c.funcName(), strings.Join(values, ", "))
+}
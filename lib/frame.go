@@ -0,0 +1,106 @@
+package godebug
+
+// This file implements "frame", "up" and "down", which select the call whose
+// variables and source the inspecting commands look at.
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// selectedFrame counts the calls out from the innermost one that print, list,
+// info locals and the like look at. It goes back to 0 at every pause, and is
+// only touched by the goroutine the debugger follows.
+var selectedFrame int
+
+// framePrompt returns p as seen from the selected frame: the scope and line of
+// that call. The Context stays the innermost one, which the commands that
+// resume the program work from.
+func framePrompt(p prompt) prompt {
+	if selectedFrame == 0 {
+		return p
+	}
+	f := frameContext(p.ctx, selectedFrame)
+	return prompt{p.ctx, f.scope, f.line}
+}
+
+// frameContext returns the Context n calls out from c, or nil if there are
+// not that many calls to generated code.
+func frameContext(c *Context, n int) *Context {
+	for ; c != nil && n > 0; n-- {
+		c = c.parent
+	}
+	return c
+}
+
+// paused returns the scope and line the program is paused at, which are p's
+// own unless an outer frame is selected.
+func (p prompt) paused() (*Scope, int) {
+	if selectedFrame == 0 {
+		return p.scope, p.line
+	}
+	return p.ctx.scope, p.ctx.line
+}
+
+// frameCommand implements "frame [n]".
+func frameCommand(c *Context, arg string) {
+	if arg == "" {
+		printFrame(c, selectedFrame)
+		return
+	}
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 0 {
+		fmt.Fprintln(output, "usage: frame [n]")
+		return
+	}
+	selectFrame(c, n)
+}
+
+// moveFrameCommand implements "up [count]" and "down [count]"; by is 1 for up
+// and -1 for down.
+func moveFrameCommand(c *Context, verb string, by int, arg string) {
+	count := 1
+	if arg != "" {
+		var err error
+		if count, err = strconv.Atoi(arg); err != nil || count < 1 {
+			fmt.Fprintf(output, "usage: %s [count]\n", verb)
+			return
+		}
+	}
+	n := selectedFrame + by*count
+	if n < 0 {
+		n = 0
+	}
+	for n > 0 && frameContext(c, n) == nil {
+		n--
+	}
+	if n == selectedFrame {
+		if by > 0 {
+			fmt.Fprintln(output, "Already at the outermost frame.")
+		} else {
+			fmt.Fprintln(output, "Already at the innermost frame.")
+		}
+		return
+	}
+	selectFrame(c, n)
+}
+
+// selectFrame makes frame n the one the inspecting commands look at.
+func selectFrame(c *Context, n int) {
+	f := frameContext(c, n)
+	switch {
+	case f == nil:
+		fmt.Fprintf(output, "There is no frame %d; backtrace lists them.\n", n)
+		return
+	case f.scope == nil:
+		fmt.Fprintf(output, "Frame %d, %s, has not reached a line yet.\n", n, f.funcName())
+		return
+	}
+	selectedFrame = n
+	printFrame(c, n)
+}
+
+// printFrame prints frame n as backtrace does.
+func printFrame(c *Context, n int) {
+	fmt.Fprintln(output, frameLine(n, backtrace(c, false)[n]))
+}
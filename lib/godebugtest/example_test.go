@@ -0,0 +1,100 @@
+package godebugtest_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mailgun/godebug/lib"
+	"github.com/mailgun/godebug/lib/godebugtest"
+)
+
+func ExampleRecord() {
+	snapshots := godebugtest.Record(func() {
+		sum(2)
+	})
+	for _, s := range snapshots {
+		fmt.Printf("%d: %s (total=%v)\n", s.Line, s.Source, s.Locals["total"])
+	}
+	// Output:
+	// 5: _ = "breakpoint" (total=0)
+	// 6: for i := 1; i <= n; i++ { (total=0)
+	// 7: total += i (total=0)
+	// 6: for i := 1; i <= n; i++ { (total=1)
+	// 7: total += i (total=1)
+	// 6: for i := 1; i <= n; i++ { (total=3)
+	// 9: return total (total=3)
+}
+
+func ExampleReplay() {
+	log := `-> _ = "breakpoint"
+(godebug) next
+-> for i := 1; i <= n; i++ {
+(godebug) print total
+0
+(godebug) continue
+`
+	fmt.Println(godebugtest.Replay(log, func() {
+		sum(2)
+	}))
+	fmt.Println(godebugtest.Replay(strings.Replace(log, "0\n", "1\n", 1), func() {
+		sum(2)
+	}))
+	// Output:
+	// <nil>
+	// line 5: got "0", want "1"
+}
+
+// sum is the code 'godebug test' generates for this function:
+//
+//	func sum(n int) int {
+//		total := 0
+//		_ = "breakpoint"
+//		for i := 1; i <= n; i++ {
+//			total += i
+//		}
+//		return total
+//	}
+func sum(n int) int {
+	var result1 int
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = sum(n)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := example_test_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 4)
+	total := 0
+	scope.Declare("total", &total)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 5)
+	{
+		scope := scope.EnteringNewChildScope()
+
+		for i := 1; i <= n; i++ {
+			godebug.Line(ctx, scope, 6)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 7)
+			total += i
+		}
+		godebug.Line(ctx, scope, 6)
+	}
+	godebug.Line(ctx, scope, 9)
+	return total
+}
+
+var example_test_go_scope = godebug.EnteringNewFile(nil, example_test_go_contents)
+
+var example_test_go_contents = `package main
+
+func sum(n int) int {
+	total := 0
+	_ = "breakpoint"
+	for i := 1; i <= n; i++ {
+		total += i
+	}
+	return total
+}
+`
@@ -0,0 +1,110 @@
+// Package godebugtest records where the godebug debugger pauses, so that tests can make
+// assertions about instrumented code without an interactive session.
+package godebugtest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/mailgun/godebug/lib"
+)
+
+// Record runs fn and returns a Snapshot for every line the debugger pauses at.
+// fn must call code instrumented by godebug, e.g. by 'godebug test'. Nothing is
+// recorded until the instrumented code reaches a breakpoint; from there the
+// debugger steps through each line until fn returns.
+//
+// Record installs a godebug.PauseHandler for the duration of the call, so it
+// must not be called concurrently with itself.
+func Record(fn func()) []godebug.Snapshot {
+	return RecordWith("step", fn)
+}
+
+// RecordWith is like Record, but answers every pause with command instead of
+// "step". For example, "next" does not record the lines of called functions and
+// "continue" records only breakpoints.
+func RecordWith(command string, fn func()) []godebug.Snapshot {
+	var (
+		mu        sync.Mutex
+		snapshots []godebug.Snapshot
+	)
+	godebug.SetPauseHandler(func(s godebug.Snapshot) string {
+		mu.Lock()
+		defer mu.Unlock()
+		snapshots = append(snapshots, s)
+		return command
+	})
+	defer godebug.SetPauseHandler(nil)
+	defer godebug.Continue()
+	fn()
+	mu.Lock()
+	defer mu.Unlock()
+	return snapshots
+}
+
+// Replay runs fn, answering the debugger's pauses with the commands in log,
+// a session recorded with the debugger's record command, and checks that the
+// debugger prints what the log says it printed. It returns an error describing
+// the first difference. The log must start at the first pause fn reaches.
+//
+// Replay sends the debugger's input and output through godebug.SetInput and
+// godebug.SetOutput for the duration of the call, so it must not be called
+// concurrently with itself or with Record.
+func Replay(log string, fn func()) error {
+	var commands []string
+	for _, line := range strings.Split(log, "\n") {
+		if strings.HasPrefix(line, "(godebug) ") {
+			commands = append(commands, strings.TrimPrefix(line, "(godebug) ")+"\n")
+		}
+	}
+	var out lockedBuffer
+	godebug.SetInput(strings.NewReader(strings.Join(commands, "")))
+	godebug.SetOutput(&out)
+	defer godebug.SetInput(nil)
+	defer godebug.SetOutput(os.Stdout)
+	defer godebug.Continue()
+	fn()
+	// A log that ends at a pause runs out of commands there, and the
+	// debugger lets the program run on.
+	got := strings.TrimSuffix(out.String(), "(godebug) quitting session\n")
+	return compareLines(got, log)
+}
+
+// compareLines describes the first line that differs between got and want.
+func compareLines(got, want string) error {
+	g := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	w := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	for i := 0; i < len(g) && i < len(w); i++ {
+		if g[i] != w[i] {
+			return fmt.Errorf("line %d: got %q, want %q", i+1, g[i], w[i])
+		}
+	}
+	switch {
+	case len(g) > len(w):
+		return fmt.Errorf("line %d: got %q after the end of the log", len(w)+1, g[len(w)])
+	case len(g) < len(w):
+		return fmt.Errorf("line %d: got nothing, want %q", len(g)+1, w[len(g)])
+	}
+	return nil
+}
+
+// lockedBuffer is a bytes.Buffer that goroutines can write to at once.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,458 @@
+package godebug
+
+// This file keeps track of the goroutines running generated code and
+// implements the commands that deal with them.
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// A goroutine holds what godebug knows about a goroutine running generated code.
+// It is stored in goroutine-local storage under goroutineKey.
+type goroutine struct {
+	// lastActive is when the goroutine last ran a line, in Unix nanoseconds,
+	// while the watchdog runs; accessed atomically. It comes first to be
+	// 64-bit aligned.
+	lastActive int64
+
+	id    uint32
+	label string   // set by LabelGoroutine; guarded by the goroutines mutex
+	depth int32    // number of generated function calls on the stack; accessed atomically
+	top   *Context // innermost generated function call; only touched by the goroutine itself
+
+	// caught is set while a panic "catch nil-deref" paused for unwinds; only
+	// touched by the goroutine itself.
+	caught bool
+
+	// pprofLabeled is set while the goroutine has followedLabels, and
+	// ownLabels holds the labels it had before; only touched by the goroutine
+	// itself.
+	pprofLabeled bool
+	ownLabels    unsafe.Pointer
+
+	// hits counts the runs of the lines in trackedLines; only touched by the
+	// goroutine itself.
+	hits map[lineKey]uint64
+}
+
+// goroutines maps ids to the goroutines that are running generated code.
+var goroutines = struct {
+	sync.Mutex
+	m map[uint32]*goroutine
+}{m: make(map[uint32]*goroutine)}
+
+func newGoroutine() *goroutine {
+	g := &goroutine{id: acquireID()}
+	goroutines.Lock()
+	goroutines.m[g.id] = g
+	goroutines.Unlock()
+	return g
+}
+
+func (g *goroutine) release() {
+	takeExit(g)
+	if atomic.LoadInt32(&pendingTraceCount) > 0 {
+		dropTrace(g.id)
+	}
+	goroutines.Lock()
+	delete(goroutines.m, g.id)
+	goroutines.Unlock()
+	ids.Release(uint(g.id))
+}
+
+// LabelGoroutine gives the calling goroutine a name, such as "worker-3", that
+// the debugger shows next to its id and that "step goroutine" accepts in place
+// of the id. Like Break, it does nothing if the goroutine has not run generated
+// code yet.
+func LabelGoroutine(label string) {
+	val, ok := context.GetValue(goroutineKey)
+	if !ok {
+		return
+	}
+	goroutines.Lock()
+	val.(*goroutine).label = label
+	goroutines.Unlock()
+}
+
+var debuggable func(label string) bool
+
+// SetDebuggableGoroutines limits which goroutines can pause the program at a
+// breakpoint statement or a call to Break. Those made by other goroutines are
+// ignored. f is called with the label the goroutine was given with
+// LabelGoroutine, or "" if it has none, so that a server can, for example,
+// debug only its request handlers and never trap in a background goroutine.
+// Passing nil, the default, lets every goroutine pause the program.
+//
+// Breakpoints set at the prompt, and stepping, are not affected. Like
+// SetPauseHandler, SetDebuggableGoroutines is meant to be called before the
+// program reaches a breakpoint.
+func SetDebuggableGoroutines(f func(label string) bool) {
+	debuggable = f
+}
+
+// isDebuggable reports whether g may pause the program at a breakpoint
+// statement or a call to Break.
+func isDebuggable(g *goroutine) bool {
+	f := debuggable
+	if f == nil {
+		return true
+	}
+	goroutines.Lock()
+	label := g.label
+	goroutines.Unlock()
+	return f(label)
+}
+
+// goroutineLabel returns the label of goroutine id, or "" if it has none.
+func goroutineLabel(id uint32) string {
+	goroutines.Lock()
+	defer goroutines.Unlock()
+	if g, ok := goroutines.m[id]; ok {
+		return g.label
+	}
+	return ""
+}
+
+// goroutineName returns how the debugger refers to goroutine id: by its id,
+// followed by its label if it has one.
+func goroutineName(id uint32) string {
+	if label := goroutineLabel(id); label != "" {
+		return fmt.Sprintf("%d (%s)", id, label)
+	}
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// findGoroutine returns the id of the known goroutine with the given label.
+func findGoroutine(label string) (id uint32, ok bool) {
+	goroutines.Lock()
+	defer goroutines.Unlock()
+	for _, g := range goroutines.m {
+		if g.label == label {
+			return g.id, true
+		}
+	}
+	return 0, false
+}
+
+// enter returns the Context for a new call on g's stack.
+func (g *goroutine) enter(pc uintptr) *Context {
+	c := &Context{goroutine: g.id, g: g, parent: g.top, pc: pc}
+	g.top = c
+	atomic.AddInt32(&g.depth, 1)
+	countCall(pc)
+	return c
+}
+
+// exit removes c, which must be the innermost call, from g's stack.
+func (g *goroutine) exit(c *Context) {
+	g.top = c.parent
+	atomic.AddInt32(&g.depth, -1)
+}
+
+// sortedGoroutines returns the known goroutines ordered by id.
+func sortedGoroutines() []*goroutine {
+	goroutines.Lock()
+	defer goroutines.Unlock()
+	gs := make([]*goroutine, 0, len(goroutines.m))
+	for _, g := range goroutines.m {
+		gs = append(gs, g)
+	}
+	sort.Sort(byID(gs))
+	return gs
+}
+
+type byID []*goroutine
+
+func (g byID) Len() int           { return len(g) }
+func (g byID) Swap(i, j int)      { g[i], g[j] = g[j], g[i] }
+func (g byID) Less(i, j int) bool { return g[i].id < g[j].id }
+
+func knownGoroutine(id uint32) bool {
+	goroutines.Lock()
+	defer goroutines.Unlock()
+	_, ok := goroutines.m[id]
+	return ok
+}
+
+// goroutineIDs lists the ids and labels of the known goroutines.
+func goroutineIDs() string {
+	gs := sortedGoroutines()
+	s := make([]string, len(gs))
+	for i, g := range gs {
+		s[i] = goroutineName(g.id)
+	}
+	return strings.Join(s, ", ")
+}
+
+// printDepths implements "info depth".
+func printDepths() {
+	max, _ := strconv.Atoi(getSetting("depth-warning"))
+	current := atomic.LoadUint32(&currentGoroutine)
+	for _, g := range sortedGoroutines() {
+		marker := " "
+		if g.id == current {
+			marker = "*"
+		}
+		depth := atomic.LoadInt32(&g.depth)
+		fmt.Fprintf(output, "%s goroutine %s: depth %d", marker, goroutineName(g.id), depth)
+		if max > 0 && int(depth) > max {
+			fmt.Fprintf(output, " (deeper than depth-warning %d)", max)
+		}
+		fmt.Fprintln(output)
+	}
+}
+
+// printGLS implements "info gls". It runs in the paused goroutine, so it
+// reads that goroutine's goroutine-local storage.
+func printGLS(c *Context) {
+	val, ok := context.GetValue(goroutineKey)
+	if !ok {
+		fmt.Fprintf(output, "goroutineKey: not set. The paused call's Context says goroutine %d.\n", c.goroutine)
+		return
+	}
+	g, ok := val.(*goroutine)
+	if !ok {
+		fmt.Fprintf(output, "goroutineKey: unexpected %T value %v\n", val, val)
+		return
+	}
+	fmt.Fprintf(output, "goroutineKey: goroutine %s, depth %d\n", goroutineName(g.id), atomic.LoadInt32(&g.depth))
+	if g.top != nil {
+		fmt.Fprintf(output, "innermost generated call: %s\n", g.top.funcName())
+	}
+	if g.id != c.goroutine {
+		fmt.Fprintf(output, "The paused call's Context says goroutine %d instead.\n", c.goroutine)
+	}
+	if !knownGoroutine(g.id) {
+		fmt.Fprintln(output, "The goroutine is missing from the list of known goroutines.")
+	}
+}
+
+// A peek is a single step taken in another goroutine by "step goroutine". The
+// goroutine that started it waits for done to be closed, after which the debugger
+// follows it again.
+type peek struct {
+	prev uint32
+	done chan struct{}
+}
+
+// activePeek is the peek waiting for its goroutine to pause, or nil.
+var activePeek *peek
+
+// stepGoroutine lets goroutine arg, given by id or label, run until its next line, pauses there, and
+// returns once the user resumes the program from that pause. The debugger's
+// stepping state is the same afterwards as it was before. ok is false if id does
+// not name another goroutine.
+func stepGoroutine(arg string) (ok bool) {
+	if arg == "" {
+		fmt.Fprintln(output, "usage: step goroutine <id>")
+		return false
+	}
+	id, ok := goroutineArg(arg)
+	if !ok {
+		return false
+	}
+	prev := atomic.LoadUint32(&currentGoroutine)
+	if id == prev {
+		fmt.Fprintf(output, "The debugger is already following goroutine %s.\n", goroutineName(id))
+		return false
+	}
+	if !knownGoroutine(id) {
+		fmt.Fprintf(output, "There is no goroutine %d running generated code. Known goroutines: %s\n", id, goroutineIDs())
+		return false
+	}
+	var (
+		savedPeek                                = activePeek
+		savedState                               = currentState
+		savedCurrentDepth, savedDebuggerDepth    = currentDepth, debuggerDepth
+		savedJustLeft, savedLastCtx, savedLastLn = justLeft, lastPause.ctx, lastPause.line
+	)
+	p := &peek{prev: prev, done: make(chan struct{})}
+	activePeek = p
+	notify("goroutine", fmt.Sprintf("< stepping goroutine %s >", goroutineName(id)))
+	lastPause.ctx = nil
+	currentState = step
+	atomic.StoreUint32(&currentGoroutine, id)
+	<-p.done
+	activePeek = savedPeek
+	currentState = savedState
+	currentDepth, debuggerDepth = savedCurrentDepth, savedDebuggerDepth
+	justLeft, lastPause.ctx, lastPause.line = savedJustLeft, savedLastCtx, savedLastLn
+	notify("goroutine", fmt.Sprintf("< back in goroutine %s >", goroutineName(prev)))
+	return true
+}
+
+// goroutineArg returns the id of the goroutine arg names by id or label. If
+// there is no goroutine with that label, it prints so and ok is false.
+func goroutineArg(arg string) (id uint32, ok bool) {
+	if n, err := strconv.ParseUint(arg, 10, 32); err == nil {
+		return uint32(n), true
+	}
+	if id, ok = findGoroutine(arg); !ok {
+		fmt.Fprintf(output, "There is no goroutine labeled %q. Known goroutines: %s\n", arg, goroutineIDs())
+	}
+	return id, ok
+}
+
+// endPeek hands control back to the goroutine that started the active peek, if
+// there is one. It is called by the peeked goroutine after its pause.
+func endPeek() {
+	p := activePeek
+	if p == nil {
+		return
+	}
+	activePeek = nil
+	// Stop following this goroutine before letting it run on, so that it does
+	// not pause again.
+	atomic.StoreUint32(&currentGoroutine, p.prev)
+	close(p.done)
+}
+
+// switchArmed is set by "continue switch" until a goroutine other than
+// switchFrom runs a line of generated code. That goroutine clears it and pauses.
+var (
+	switchArmed int32
+	switchFrom  uint32
+)
+
+// continueSwitch resumes the program until another goroutine runs generated code.
+func continueSwitch() {
+	atomic.StoreUint32(&switchFrom, atomic.LoadUint32(&currentGoroutine))
+	atomic.StoreInt32(&switchArmed, 1)
+	currentState = run
+}
+
+// takeSwitch makes the debugger follow the goroutine running c and pause at
+// its current line, if "continue switch" is waiting for a goroutine like it.
+func takeSwitch(c *Context) {
+	from := atomic.LoadUint32(&switchFrom)
+	if c.goroutine == from || !atomic.CompareAndSwapInt32(&switchArmed, 1, 0) {
+		return
+	}
+	atomic.StoreUint32(&currentGoroutine, c.goroutine)
+	lastPause.ctx = nil
+	currentState = step
+	notify("goroutine", fmt.Sprintf("< switched from goroutine %s to goroutine %s >", goroutineName(from), goroutineName(c.goroutine)))
+}
+
+// exitArmed is set by "wait goroutine" until goroutine exitID returns from its
+// outermost call to generated code. That goroutine clears it on its way out.
+var (
+	exitArmed int32
+	exitID    uint32
+)
+
+// waitGoroutine resumes the program until goroutine arg, given by id or label,
+// exits. ok is false if arg does not name a known goroutine.
+func waitGoroutine(arg string) (ok bool) {
+	if arg == "" {
+		fmt.Fprintln(output, "usage: wait goroutine <id>")
+		return false
+	}
+	id, ok := goroutineArg(arg)
+	if !ok {
+		return false
+	}
+	if !knownGoroutine(id) {
+		fmt.Fprintf(output, "There is no goroutine %d running generated code. Known goroutines: %s\n", id, goroutineIDs())
+		return false
+	}
+	atomic.StoreUint32(&exitID, id)
+	atomic.StoreInt32(&exitArmed, 1)
+	currentState = run
+	return true
+}
+
+// takeExit is called when g returns from its outermost call to generated code.
+// If "wait goroutine" is waiting for g, it says so, and if the program is
+// running, the next line another goroutine runs is paused at, as with
+// "continue switch".
+func takeExit(g *goroutine) {
+	if atomic.LoadUint32(&exitID) != g.id || !atomic.CompareAndSwapInt32(&exitArmed, 1, 0) {
+		return
+	}
+	notify("goroutine", fmt.Sprintf("< goroutine %s exited >", goroutineName(g.id)))
+	if currentState == run {
+		atomic.StoreUint32(&switchFrom, g.id)
+		atomic.StoreInt32(&switchArmed, 1)
+	}
+}
+
+// spawnArmed is set when step runs a go statement with follow-spawn on. The
+// next goroutine to run generated code for the first time clears it, becomes
+// the goroutine the debugger follows, and closes spawned.
+var (
+	spawnArmed int32
+	spawnFrom  uint32
+	spawned    chan struct{}
+)
+
+// spawnWait is how long the goroutine that ran a go statement waits at its
+// next line for the new goroutine to start, before it pauses there instead.
+const spawnWait = time.Second
+
+// isGoStatement reports whether a line of source starts a goroutine.
+func isGoStatement(src string) bool {
+	return strings.HasPrefix(src, "go ") || strings.HasPrefix(src, "go\t")
+}
+
+// armSpawn makes the debugger follow the goroutine started by the go statement
+// goroutine id is about to run.
+func armSpawn(id uint32) {
+	spawned = make(chan struct{})
+	atomic.StoreUint32(&spawnFrom, id)
+	atomic.StoreInt32(&spawnArmed, 1)
+}
+
+// takeSpawn is called when g runs generated code for the first time. If a go
+// statement is being stepped over with follow-spawn on, the debugger follows g
+// from here on.
+func takeSpawn(g *goroutine) {
+	if atomic.LoadInt32(&spawnArmed) == 0 || !atomic.CompareAndSwapInt32(&spawnArmed, 1, 0) {
+		return
+	}
+	atomic.StoreUint32(&currentGoroutine, g.id)
+	lastPause.ctx = nil
+	currentState = step
+	notify("goroutine", fmt.Sprintf("< following new goroutine %d >", g.id))
+	close(spawned)
+}
+
+// awaitSpawn is called at the first line the goroutine that ran a go
+// statement reaches afterwards. It gives the new goroutine up to spawnWait to
+// start before that line is paused at as usual.
+func awaitSpawn() {
+	select {
+	case <-spawned:
+	case <-time.After(spawnWait):
+		if atomic.CompareAndSwapInt32(&spawnArmed, 1, 0) {
+			notify("goroutine", "< the new goroutine did not run generated code; staying in this one >")
+		} else {
+			<-spawned
+		}
+	}
+}
+
+// acquireID returns an id for a goroutine that is running generated code for
+// the first time.
+func acquireID() uint32 {
+	if getSetting("goroutine-ids") == "sequential" {
+		return uint32(ids.AcquireNew())
+	}
+	return uint32(ids.Acquire())
+}
+
+// AcquireNew is like Acquire, but never returns an id that was used before.
+func (p *idPool) AcquireNew() (id uint) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	id = p.max_id
+	p.max_id++
+	return id
+}
@@ -0,0 +1,226 @@
+package godebug
+
+// This file implements "set print-gosyntax strict".
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// goSyntax renders v as a Go expression that evaluates to an equal value, so
+// that it can be pasted into a test. Parts that cannot be written as a literal,
+// such as non-nil funcs and channels, pointers to non-composite values, and
+// pointer cycles, are replaced by nil followed by a comment explaining why.
+//
+// Types are written the way reflect names them, e.g. main.T, so code in the same
+// package as the value has to drop the package qualifier. Unexported fields are
+// included, which only compiles in the package that declares them.
+//
+// If max is positive, goSyntax stops writing elements once the result is
+// longer than max bytes, leaving it incomplete.
+func goSyntax(v reflect.Value, max int) string {
+	w := goSyntaxWriter{max: max}
+	w.value(v, true)
+	return w.String()
+}
+
+type goSyntaxWriter struct {
+	bytes.Buffer
+	visiting map[uintptr]bool
+	max      int
+}
+
+func (w *goSyntaxWriter) full() bool {
+	return w.max > 0 && w.Len() > w.max
+}
+
+func (w *goSyntaxWriter) unrepresentable(why string) {
+	fmt.Fprintf(w, "nil /* godebug: %s */", why)
+}
+
+// value writes v. If typed is false, v is inside a composite literal whose
+// type already determines v's type, so v may be written as an untyped constant.
+func (w *goSyntaxWriter) value(v reflect.Value, typed bool) {
+	if w.full() {
+		return
+	}
+	if !v.IsValid() {
+		w.WriteString("nil")
+		return
+	}
+	t := v.Type()
+	switch v.Kind() {
+	case reflect.Bool:
+		w.basic(t, strconv.FormatBool(v.Bool()), typed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		w.basic(t, strconv.FormatInt(v.Int(), 10), typed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		w.basic(t, strconv.FormatUint(v.Uint(), 10), typed)
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			w.unrepresentable(fmt.Sprintf("%v has no literal; use the math package", f))
+			return
+		}
+		s := strconv.FormatFloat(f, 'g', -1, t.Bits())
+		if !strings.ContainsAny(s, ".e") {
+			s += ".0"
+		}
+		w.basic(t, s, typed)
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		w.basic(t, fmt.Sprintf("complex(%v, %v)", real(c), imag(c)), typed)
+	case reflect.String:
+		w.basic(t, strconv.Quote(v.String()), typed)
+	case reflect.Interface:
+		if v.IsNil() {
+			w.WriteString("nil")
+			return
+		}
+		w.value(v.Elem(), true)
+	case reflect.Ptr:
+		if v.IsNil() {
+			fmt.Fprintf(w, "(%s)(nil)", t)
+			return
+		}
+		switch t.Elem().Kind() {
+		case reflect.Struct, reflect.Array, reflect.Slice, reflect.Map:
+		default:
+			w.unrepresentable(fmt.Sprintf("cannot take the address of a %s literal", t.Elem()))
+			return
+		}
+		if w.visiting[v.Pointer()] {
+			w.unrepresentable("cycle")
+			return
+		}
+		if w.visiting == nil {
+			w.visiting = make(map[uintptr]bool)
+		}
+		w.visiting[v.Pointer()] = true
+		w.WriteString("&")
+		w.value(v.Elem(), true)
+		delete(w.visiting, v.Pointer())
+	case reflect.Slice:
+		if v.IsNil() {
+			fmt.Fprintf(w, "%s(nil)", t)
+			return
+		}
+		fallthrough
+	case reflect.Array:
+		fmt.Fprintf(w, "%s{", t)
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			w.value(v.Index(i), false)
+		}
+		w.WriteString("}")
+	case reflect.Map:
+		if v.IsNil() {
+			fmt.Fprintf(w, "%s(nil)", t)
+			return
+		}
+		// Sort the entries so that the output does not change between runs.
+		entries := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			// Entries are not cut short here, since which ones are left out
+			// would depend on the map's iteration order.
+			e := goSyntaxWriter{visiting: w.visiting, max: w.max}
+			e.value(k, false)
+			e.WriteString(": ")
+			e.value(v.MapIndex(k), false)
+			entries = append(entries, e.String())
+		}
+		sort.Strings(entries)
+		fmt.Fprintf(w, "%s{%s}", t, strings.Join(entries, ", "))
+	case reflect.Struct:
+		fmt.Fprintf(w, "%s{", t)
+		first := true
+		for i := 0; i < v.NumField(); i++ {
+			// Zero fields are left out; the literal gives them their zero value anyway.
+			if isZero(v.Field(i)) {
+				continue
+			}
+			if !first {
+				w.WriteString(", ")
+			}
+			first = false
+			fmt.Fprintf(w, "%s: ", t.Field(i).Name)
+			w.value(v.Field(i), false)
+		}
+		w.WriteString("}")
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		if v.IsNil() {
+			fmt.Fprintf(w, "(%s)(nil)", t)
+			return
+		}
+		w.unrepresentable(fmt.Sprintf("a non-nil %s cannot be written as a literal", t))
+	default:
+		w.unrepresentable(fmt.Sprintf("cannot write a %s", t))
+	}
+}
+
+// basic writes the literal s of type t, converting it to t where the literal
+// alone would have a different default type.
+func (w *goSyntaxWriter) basic(t reflect.Type, s string, typed bool) {
+	if !typed || t.PkgPath() == "" && t.Name() == defaultType(t.Kind()) {
+		w.WriteString(s)
+		return
+	}
+	fmt.Fprintf(w, "%s(%s)", t, s)
+}
+
+func defaultType(k reflect.Kind) string {
+	switch k {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int:
+		return "int"
+	case reflect.Float64:
+		return "float64"
+	case reflect.Complex128:
+		return "complex128"
+	case reflect.String:
+		return "string"
+	}
+	return ""
+}
+
+func isZero(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0 && !math.Signbit(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		return v.Complex() == 0
+	case reflect.String:
+		return v.Len() == 0
+	case reflect.Interface, reflect.Ptr, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return v.IsNil()
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !isZero(v.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !isZero(v.Field(i)) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package godebug
+
+// This file implements "print <expression> @<pause>", which evaluates an
+// expression with the locals as they were at an earlier pause.
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// splitPauseRef splits a trailing "@<pause>" off of a print argument, as in
+// "x @-2". ok is false if there is none.
+func splitPauseRef(args string) (expr, ref string, ok bool) {
+	i := strings.LastIndex(args, "@")
+	if i <= 0 || !isSpace(rune(args[i-1])) {
+		return args, "", false
+	}
+	if _, err := strconv.Atoi(args[i+1:]); err != nil {
+		return args, "", false
+	}
+	return strings.TrimSpace(args[:i]), args[i+1:], true
+}
+
+// findPause returns the recorded pause that ref refers to. A negative ref
+// counts back from the current pause, so -1 is the previous one. Otherwise
+// ref is the number of the pause, counting from 1 at the first pause.
+func findPause(ref string) (p pauseLocals, ok bool) {
+	n, _ := strconv.Atoi(ref)
+	if n <= 0 {
+		n += curLocals.n
+	}
+	for _, p := range pauseHistory {
+		if p.n == n {
+			return p, true
+		}
+	}
+	if len(pauseHistory) == 0 {
+		fmt.Fprintln(output, "There are no recorded pauses.")
+	} else {
+		fmt.Fprintf(output, "Pause @%s is not recorded. This is pause @%d; pauses @%d to @%d are recorded.\n",
+			ref, curLocals.n, pauseHistory[0].n, curLocals.n)
+	}
+	return pauseLocals{}, false
+}
+
+// pastScope returns a scope in which the locals of p have the values they had
+// at that pause. Everything else, such as globals, has its current value.
+func (p pauseLocals) pastScope() *Scope {
+	vars := make(map[string]interface{}, len(p.locals))
+	for name, v := range p.locals {
+		var ptr reflect.Value
+		if v == nil {
+			ptr = reflect.New(reflect.TypeOf((*interface{})(nil)).Elem())
+		} else {
+			ptr = reflect.New(reflect.TypeOf(v))
+			ptr.Elem().Set(reflect.ValueOf(copyInterface(v, newCopyBudget())))
+		}
+		vars[name] = ptr.Interface()
+	}
+	return &Scope{
+		Vars:   vars,
+		Consts: make(map[string]interface{}),
+		Funcs:  make(map[string]interface{}),
+		parent: p.scope,
+		file:   p.scope.file,
+	}
+}
+
+func printPastExpr(expr, ref, format string) {
+	p, ok := findPause(ref)
+	if !ok {
+		return
+	}
+	printExpr(p.pastScope(), expr, format)
+}
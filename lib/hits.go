@@ -0,0 +1,87 @@
+package godebug
+
+// This file implements "info hits", which says how many times the current line
+// has run in the paused goroutine.
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+type lineKey struct {
+	file *sourceFile
+	line int
+}
+
+// less orders lines by file path, then line number.
+func (k lineKey) less(o lineKey) bool {
+	if k.file.name != o.file.name {
+		return k.file.name < o.file.name
+	}
+	return k.line < o.line
+}
+
+// trackedLines holds the lines whose runs are counted: those the debugger has
+// paused at and those with breakpoints. Every line of generated code consults
+// it, so it is a map[lineKey]bool that is replaced rather than changed, and
+// read without a lock. trackedLinesMu serializes the replacements.
+var (
+	trackedLines   atomic.Value
+	trackedLinesMu sync.Mutex
+)
+
+// trackLine starts counting the runs of a line. It reports whether the line
+// was not counted before.
+func trackLine(file *sourceFile, line int) bool {
+	trackedLinesMu.Lock()
+	defer trackedLinesMu.Unlock()
+	old, _ := trackedLines.Load().(map[lineKey]bool)
+	key := lineKey{file, line}
+	if old[key] {
+		return false
+	}
+	m := make(map[lineKey]bool, len(old)+1)
+	for k := range old {
+		m[k] = true
+	}
+	m[key] = true
+	trackedLines.Store(m)
+	return true
+}
+
+// countHit counts a run of a line by c's goroutine, if the line is tracked.
+func countHit(c *Context, s *Scope, line int) {
+	m, _ := trackedLines.Load().(map[lineKey]bool)
+	key := lineKey{s.file, line}
+	if !m[key] {
+		return
+	}
+	if c.g.hits == nil {
+		c.g.hits = make(map[lineKey]uint64)
+	}
+	c.g.hits[key]++
+}
+
+// trackPausedLine is called when c's goroutine pauses at a line. A line that
+// was not tracked has run at least this once.
+func trackPausedLine(c *Context, s *Scope, line int) {
+	if s.file == nil || !trackLine(s.file, line) {
+		return
+	}
+	if c.g.hits == nil {
+		c.g.hits = make(map[lineKey]uint64)
+	}
+	c.g.hits[lineKey{s.file, line}] = 1
+}
+
+// printHits implements "info hits". It runs on the paused goroutine, which is
+// the only one that touches its counts.
+func printHits(c *Context, s *Scope, line int) {
+	n := c.g.hits[lineKey{s.file, line}]
+	times := "times"
+	if n == 1 {
+		times = "time"
+	}
+	fmt.Fprintf(output, "Line %d has run %d %s in goroutine %s, counting from the first pause or breakpoint there.\n", line, n, times, goroutineName(c.goroutine))
+}
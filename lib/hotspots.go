@@ -0,0 +1,90 @@
+package godebug
+
+// This file implements "info hotspots" and "reset hotspots", which show the
+// lines the debugger pauses at most often.
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// pauses counts the pauses at each line since the session started or the
+// counts were reset.
+var pauses = struct {
+	sync.Mutex
+	m map[lineKey]uint64
+}{m: make(map[lineKey]uint64)}
+
+func countPause(s *Scope, line int) {
+	if s.file == nil {
+		return
+	}
+	pauses.Lock()
+	pauses.m[lineKey{s.file, line}]++
+	pauses.Unlock()
+}
+
+type hotspot struct {
+	lineKey
+	count uint64
+}
+
+// hotspots returns the lines paused at, most paused at first.
+func hotspots() []hotspot {
+	pauses.Lock()
+	spots := make([]hotspot, 0, len(pauses.m))
+	for key, n := range pauses.m {
+		spots = append(spots, hotspot{key, n})
+	}
+	pauses.Unlock()
+	sort.Sort(byPauses(spots))
+	return spots
+}
+
+// printHotspots implements "info hotspots [count]", which prints the count
+// lines paused at most, 10 if count is not given.
+func printHotspots(args string) {
+	max := 10
+	if args = strings.TrimSpace(args); args != "" {
+		n, err := strconv.Atoi(args)
+		if err != nil || n < 1 {
+			fmt.Fprintln(output, "usage: info hotspots [count]")
+			return
+		}
+		max = n
+	}
+	spots := hotspots()
+	if len(spots) == 0 {
+		fmt.Fprintln(output, "No pauses counted.")
+		return
+	}
+	if len(spots) > max {
+		spots = spots[:max]
+	}
+	width := len(strconv.FormatUint(spots[0].count, 10))
+	for _, h := range spots {
+		s := &Scope{file: h.file}
+		fmt.Fprintf(output, "%*d %s:%d: %s\n", width, h.count, h.file.name, h.line, s.sourceLine(h.line))
+	}
+}
+
+func resetHotspots() {
+	pauses.Lock()
+	pauses.m = make(map[lineKey]uint64)
+	pauses.Unlock()
+	fmt.Fprintln(output, "Pause counts reset.")
+}
+
+type byPauses []hotspot
+
+func (h byPauses) Len() int      { return len(h) }
+func (h byPauses) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h byPauses) Less(i, j int) bool {
+	if h[i].count != h[j].count {
+		return h[i].count > h[j].count
+	}
+	return h[i].lineKey.less(h[j].lineKey)
+}
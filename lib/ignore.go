@@ -0,0 +1,135 @@
+package godebug
+
+// This file implements "ignore line", which keeps the debugger from pausing at
+// a line, and "info ignored" and "noignore".
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ignoredLines holds the lines given to "ignore line". Like trackedLines, it
+// is a map[lineKey]bool that is replaced rather than changed. ignoredCount
+// lets goroutines skip the lookup when no line is ignored.
+var (
+	ignoredLines   atomic.Value
+	ignoredLinesMu sync.Mutex
+	ignoredCount   int32
+)
+
+const ignoreUsage = "usage: ignore line [<file>:]<line>"
+
+// parseFileLine parses "<line>", for a line of file, or "<file>:<line>", for
+// a line of the generated file whose path ends with <file>. If file is nil,
+// <file> is required. If arg is not one of these, or names no line,
+// parseFileLine prints why and ok is false.
+func parseFileLine(file *sourceFile, arg, usage string) (key lineKey, ok bool) {
+	n := arg
+	if i := strings.LastIndex(arg, ":"); i >= 0 {
+		if file, ok = findSourceFile(arg[:i]); !ok {
+			return lineKey{}, false
+		}
+		n = arg[i+1:]
+	}
+	line, err := strconv.Atoi(n)
+	if err != nil || file == nil {
+		fmt.Fprintln(output, usage)
+		return lineKey{}, false
+	}
+	if max := len(file.text()); line < 1 || line > max {
+		fmt.Fprintf(output, "There is no line %d; the file has %d lines.\n", line, max)
+		return lineKey{}, false
+	}
+	return lineKey{file, line}, true
+}
+
+func ignoreLineCommand(s *Scope, arg string) {
+	if arg = strings.TrimSpace(arg); arg == "" {
+		fmt.Fprintln(output, ignoreUsage)
+		return
+	}
+	key, ok := parseFileLine(s.file, arg, ignoreUsage)
+	if !ok {
+		return
+	}
+	ignoredLinesMu.Lock()
+	defer ignoredLinesMu.Unlock()
+	old, _ := ignoredLines.Load().(map[lineKey]bool)
+	if old[key] {
+		fmt.Fprintf(output, "Line %d of %s is already ignored.\n", key.line, key.file.name)
+		return
+	}
+	m := make(map[lineKey]bool, len(old)+1)
+	for k := range old {
+		m[k] = true
+	}
+	m[key] = true
+	ignoredLines.Store(m)
+	atomic.StoreInt32(&ignoredCount, int32(len(m)))
+	fmt.Fprintf(output, "Line %d of %s will not pause.\n", key.line, key.file.name)
+}
+
+// ignoredLine reports whether the debugger must not pause at the line.
+func ignoredLine(s *Scope, line int) bool {
+	m, _ := ignoredLines.Load().(map[lineKey]bool)
+	return m[lineKey{s.file, line}]
+}
+
+// noignoreCommand implements "noignore", which forgets every ignored line, and
+// "noignore [<file>:]<line>", which forgets one.
+func noignoreCommand(s *Scope, arg string) {
+	ignoredLinesMu.Lock()
+	defer ignoredLinesMu.Unlock()
+	if arg = strings.TrimSpace(arg); arg == "" {
+		ignoredLines.Store(map[lineKey]bool(nil))
+		atomic.StoreInt32(&ignoredCount, 0)
+		fmt.Fprintln(output, "No lines are ignored now.")
+		return
+	}
+	key, ok := parseFileLine(s.file, arg, "usage: noignore [[<file>:]<line>]")
+	if !ok {
+		return
+	}
+	old, _ := ignoredLines.Load().(map[lineKey]bool)
+	if !old[key] {
+		fmt.Fprintf(output, "Line %d of %s is not ignored.\n", key.line, key.file.name)
+		return
+	}
+	m := make(map[lineKey]bool, len(old))
+	for k := range old {
+		if k != key {
+			m[k] = true
+		}
+	}
+	ignoredLines.Store(m)
+	atomic.StoreInt32(&ignoredCount, int32(len(m)))
+	fmt.Fprintf(output, "Line %d of %s will pause again.\n", key.line, key.file.name)
+}
+
+// printIgnored implements "info ignored".
+func printIgnored() {
+	m, _ := ignoredLines.Load().(map[lineKey]bool)
+	if len(m) == 0 {
+		fmt.Fprintln(output, "No lines are ignored.")
+		return
+	}
+	keys := make(byLine, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Sort(keys)
+	for _, k := range keys {
+		s := &Scope{file: k.file}
+		fmt.Fprintf(output, "%s:%d: %s\n", k.file.name, k.line, s.sourceLine(k.line))
+	}
+}
+
+type byLine []lineKey
+
+func (k byLine) Len() int           { return len(k) }
+func (k byLine) Swap(i, j int)      { k[i], k[j] = k[j], k[i] }
+func (k byLine) Less(i, j int) bool { return k[i].less(k[j]) }
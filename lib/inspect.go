@@ -0,0 +1,220 @@
+package godebug
+
+// This file implements the "inspect" command, which shows a value as a tree
+// whose nodes can be expanded and collapsed one at a time.
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// inspectInteractive runs the pager for a value. It is set when the debugger
+// talks to a terminal; otherwise inspect falls back to print.
+var inspectInteractive func(root *inspectNode)
+
+// inspectPageSize is how many rows of the tree the pager shows at once.
+const inspectPageSize = 20
+
+// An inspectNode is a row in the tree shown by inspect. Its children are only
+// built when it is first expanded, so that inspecting a large value stays cheap.
+type inspectNode struct {
+	label    string
+	v        reflect.Value
+	depth    int
+	expanded bool
+	children []*inspectNode
+	loaded   bool
+}
+
+func inspectExpr(scope *Scope, expr string) {
+	if inspectInteractive == nil || pauseHandler != nil {
+		printExpr(scope, expr, "")
+		return
+	}
+	v, ok := evalValue(scope, expr)
+	if !ok {
+		return
+	}
+	if v.CanInterface() {
+		v = reflect.ValueOf(redact(expr, v.Interface()))
+	}
+	inspectInteractive(&inspectNode{label: expr, v: v})
+}
+
+// elem follows pointers and interfaces to the value they hold.
+func (n *inspectNode) elem() reflect.Value {
+	v := n.v
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+// expandable reports whether the node has children.
+func (n *inspectNode) expandable() bool {
+	v := n.elem()
+	if !v.IsValid() {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		return v.NumField() > 0
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len() > 0
+	}
+	return false
+}
+
+func (n *inspectNode) loadChildren() {
+	if n.loaded {
+		return
+	}
+	n.loaded = true
+	v := n.elem()
+	add := func(label string, c reflect.Value) {
+		n.children = append(n.children, &inspectNode{label: label, v: c, depth: n.depth + 1})
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			add(v.Type().Field(i).Name, v.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			add(fmt.Sprintf("[%d]", i), v.Index(i))
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Sort(byString(keys))
+		for _, k := range keys {
+			add("["+valueString("", k)+"]", v.MapIndex(k))
+		}
+	}
+}
+
+// String renders the node as a single row of the tree.
+func (n *inspectNode) String() string {
+	indent := strings.Repeat("  ", n.depth)
+	if !n.expandable() {
+		return indent + "  " + n.label + ": " + valueString(n.label, n.v)
+	}
+	mark := "+ "
+	if n.expanded {
+		mark = "- "
+	}
+	v := n.elem()
+	summary := v.Type().String()
+	if v.Kind() != reflect.Struct {
+		summary += fmt.Sprintf(" (len %d)", v.Len())
+	}
+	return indent + mark + n.label + ": " + summary
+}
+
+// rows lists the nodes that are visible, in the order they are shown.
+func (n *inspectNode) rows() []*inspectNode {
+	rows := []*inspectNode{n}
+	if n.expanded {
+		for _, c := range n.children {
+			rows = append(rows, c.rows()...)
+		}
+	}
+	return rows
+}
+
+// toggle expands or collapses the node.
+func (n *inspectNode) toggle() {
+	if !n.expandable() {
+		return
+	}
+	n.loadChildren()
+	n.expanded = !n.expanded
+}
+
+// An inspectView is the state of the pager: the tree, the selected row and
+// the first row on screen.
+type inspectView struct {
+	root     *inspectNode
+	selected int
+	top      int
+}
+
+// The keys the pager understands.
+const (
+	keyUp = iota
+	keyDown
+	keyRight
+	keyLeft
+	keyToggle
+	keyPageUp
+	keyPageDown
+	keyQuit
+)
+
+// handle applies a key to the view and reports whether the pager should keep
+// running.
+func (iv *inspectView) handle(key int) bool {
+	rows := iv.root.rows()
+	n := rows[iv.selected]
+	switch key {
+	case keyUp:
+		iv.selected--
+	case keyDown:
+		iv.selected++
+	case keyPageUp:
+		iv.selected -= inspectPageSize
+	case keyPageDown:
+		iv.selected += inspectPageSize
+	case keyRight:
+		if !n.expanded {
+			n.toggle()
+		} else if n.expandable() {
+			iv.selected++
+		}
+	case keyLeft:
+		if n.expanded {
+			n.toggle()
+		} else {
+			// Move to the parent, which is the closest row above that is less indented.
+			for i := iv.selected - 1; i >= 0; i-- {
+				if rows[i].depth < n.depth {
+					iv.selected = i
+					break
+				}
+			}
+		}
+	case keyToggle:
+		n.toggle()
+	case keyQuit:
+		return false
+	}
+	rows = iv.root.rows()
+	if iv.selected >= len(rows) {
+		iv.selected = len(rows) - 1
+	}
+	if iv.selected < 0 {
+		iv.selected = 0
+	}
+	if iv.selected < iv.top {
+		iv.top = iv.selected
+	}
+	if iv.selected >= iv.top+inspectPageSize {
+		iv.top = iv.selected - inspectPageSize + 1
+	}
+	return true
+}
+
+// page renders the rows on screen, marking the selected one.
+func (iv *inspectView) page() []string {
+	rows := iv.root.rows()
+	var lines []string
+	for i := iv.top; i < len(rows) && i < iv.top+inspectPageSize; i++ {
+		prefix := getSetting("context-marker")
+		if i == iv.selected {
+			prefix = getSetting("marker")
+		}
+		lines = append(lines, prefix+rows[i].String())
+	}
+	return lines
+}
@@ -0,0 +1,79 @@
+// +build !js
+
+package godebug
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+)
+
+// inspectTerminal runs the inspect pager on the terminal. It reads keys one
+// at a time in the terminal's raw mode and redraws the page after each one.
+func inspectTerminal(root *inspectNode) {
+	checkReadlineErr(rawMode.ApplyMode())
+	defer func() {
+		checkReadlineErr(origMode.ApplyMode())
+	}()
+	iv := &inspectView{root: root}
+	root.toggle()
+	r := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Fprint(output, "\x1b[H\x1b[2J")
+		for _, line := range iv.page() {
+			fmt.Fprint(output, line, "\r\n")
+		}
+		fmt.Fprint(output, "\r\n(arrows or hjkl move and expand, enter toggles, q quits)")
+		key, ok := readInspectKey(r)
+		if !ok || !iv.handle(key) {
+			break
+		}
+	}
+	fmt.Fprint(output, "\x1b[H\x1b[2J")
+}
+
+// readInspectKey reads a key press and reports it as one of the pager's keys.
+// Other keys are read and ignored.
+func readInspectKey(r *bufio.Reader) (key int, ok bool) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return keyQuit, false
+		}
+		switch b {
+		case 'k':
+			return keyUp, true
+		case 'j':
+			return keyDown, true
+		case 'l':
+			return keyRight, true
+		case 'h':
+			return keyLeft, true
+		case '\r', '\n', ' ':
+			return keyToggle, true
+		case 'q', 3, 4: // q, ctrl-c, ctrl-d
+			return keyQuit, true
+		case 0x1b:
+			// Arrow and paging keys arrive as ESC [ A, ESC [ 5 ~ and so on.
+			if b, _ = r.ReadByte(); b != '[' {
+				continue
+			}
+			switch b, _ = r.ReadByte(); b {
+			case 'A':
+				return keyUp, true
+			case 'B':
+				return keyDown, true
+			case 'C':
+				return keyRight, true
+			case 'D':
+				return keyLeft, true
+			case '5', '6':
+				r.ReadByte() // ~
+				if b == '5' {
+					return keyPageUp, true
+				}
+				return keyPageDown, true
+			}
+		}
+	}
+}
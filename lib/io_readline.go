@@ -28,7 +28,7 @@ func init() {
 	//	abbreviated initialization, which lets us test the library in a way that
 	//	is at least somewhat similar to the normal path.
 	if buildMode == "test" {
-		fmt.Println("godebug: test mode build")
+		fmt.Fprintln(output, "godebug: test mode build")
 		line = liner.NewLiner()
 		promptUser = promptUserReadline
 		return
@@ -47,13 +47,15 @@ func init() {
 	}
 	checkReadlineErr(origMode.ApplyMode())
 	promptUser = promptUserReadline
+	inspectInteractive = inspectTerminal
+	pinRegion = &terminalPins{}
 }
 
 var stopBugging = false
 
 func checkReadlineErr(err error) {
 	if err != nil && !stopBugging {
-		fmt.Println("\nWhoops! You found a godebug issue. Could you report it at https://github.com/mailgun/godebug/issues/new ?\nWe failed to adjust the terminal mode because of this error:", err)
+		fmt.Fprintln(output, "\nWhoops! You found a godebug issue. Could you report it at https://github.com/mailgun/godebug/issues/new ?\nWe failed to adjust the terminal mode because of this error:", err)
 		stopBugging = true
 	}
 }
@@ -67,7 +69,7 @@ func promptUserReadline() (response string, ok bool) {
 	}
 	s, err := line.Prompt("(godebug) ")
 	if err != nil {
-		fmt.Println("readline error:", err)
+		fmt.Fprintln(output, "readline error:", err)
 		return "", false
 	}
 	if strings.TrimSpace(s) != "" {
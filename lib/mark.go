@@ -0,0 +1,118 @@
+package godebug
+
+// This file implements "mark" and "delta", which measure how much a number or
+// the length of a collection changes between two pauses.
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// A mark is the value of an expression when "mark" was entered: a number, or
+// for strings and collections, a length.
+type mark struct {
+	length bool
+	i      int64
+	u      uint64
+	f      float64
+	kind   reflect.Kind
+}
+
+// marks holds the marks by expression. It is only touched while paused.
+var marks = make(map[string]mark)
+
+// markOf measures v. ok is false if v is neither a number nor has a length.
+func markOf(v reflect.Value) (m mark, ok bool) {
+	m.kind = v.Kind()
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		m.i = v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		m.u = v.Uint()
+	case reflect.Float32, reflect.Float64:
+		m.f = v.Float()
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Chan, reflect.Array:
+		m.length, m.i = true, int64(v.Len())
+	default:
+		return m, false
+	}
+	return m, true
+}
+
+func (m mark) String() string {
+	if m.length {
+		return fmt.Sprintf("len %d", m.i)
+	}
+	return m.number()
+}
+
+// number is the number m holds, whether a value or a length.
+func (m mark) number() string {
+	switch {
+	case m.kind >= reflect.Uint && m.kind <= reflect.Uintptr:
+		return fmt.Sprint(m.u)
+	case m.kind == reflect.Float32 || m.kind == reflect.Float64:
+		return fmt.Sprint(m.f)
+	}
+	return fmt.Sprint(m.i)
+}
+
+// since describes the change from old to m, which have the same kind.
+func (m mark) since(old mark) string {
+	switch {
+	case m.kind >= reflect.Uint && m.kind <= reflect.Uintptr:
+		if m.u >= old.u {
+			return fmt.Sprintf("+%d", m.u-old.u)
+		}
+		return fmt.Sprintf("-%d", old.u-m.u)
+	case m.kind == reflect.Float32 || m.kind == reflect.Float64:
+		return fmt.Sprintf("%+g", m.f-old.f)
+	}
+	return fmt.Sprintf("%+d", m.i-old.i)
+}
+
+// markCommand implements "mark <expression>".
+func markCommand(scope *Scope, expr string) {
+	if expr == "" {
+		fmt.Fprintln(output, "usage: mark <expression>")
+		return
+	}
+	v, ok := evalValue(scope, expr)
+	if !ok {
+		return
+	}
+	m, ok := markOf(v)
+	if !ok {
+		fmt.Fprintf(output, "%s is a %s; mark only works on numbers, strings and collections\n", expr, v.Type())
+		return
+	}
+	marks[expr] = m
+	fmt.Fprintf(output, "Marked %s at %s.\n", expr, m)
+}
+
+// deltaCommand implements "delta <expression>".
+func deltaCommand(scope *Scope, expr string) {
+	if expr == "" {
+		fmt.Fprintln(output, "usage: delta <expression>")
+		return
+	}
+	old, ok := marks[expr]
+	if !ok {
+		fmt.Fprintf(output, "%s is not marked; use mark %s first.\n", expr, expr)
+		return
+	}
+	v, ok := evalValue(scope, expr)
+	if !ok {
+		return
+	}
+	m, ok := markOf(v)
+	if !ok || m.kind != old.kind {
+		fmt.Fprintf(output, "%s is now a %s and cannot be compared with its mark.\n", expr, v.Type())
+		return
+	}
+	what := expr
+	if m.length {
+		what = "len(" + expr + ")"
+	}
+	fmt.Fprintf(output, "%s: %s since the mark (%s -> %s)\n", what, m.since(old), old.number(), m.number())
+}
@@ -0,0 +1,84 @@
+package godebug
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+)
+
+var (
+	output io.Writer = numbered
+	logger *log.Logger
+)
+
+// destination is where output writes to.
+var destination io.Writer = os.Stdout
+
+var numbered = &numberedOutput{}
+
+// SetOutput sets the destination for everything the debugger prints.
+// The default is os.Stdout.
+func SetOutput(w io.Writer) {
+	destination = w
+}
+
+// numberedOutput writes to destination. When the line-numbers setting is on,
+// it starts each line with a sequence number.
+type numberedOutput struct {
+	sync.Mutex
+	n       int
+	midLine bool
+}
+
+func (o *numberedOutput) Write(p []byte) (int, error) {
+	record(p)
+	if getSetting("line-numbers") == "off" {
+		return destination.Write(p)
+	}
+	o.Lock()
+	defer o.Unlock()
+	written := len(p)
+	var b []byte
+	for len(p) > 0 {
+		if !o.midLine {
+			o.n++
+			b = append(b, fmt.Sprintf("[%d] ", o.n)...)
+		}
+		i := bytes.IndexByte(p, '\n') + 1
+		if i == 0 {
+			i = len(p)
+		}
+		b = append(b, p[:i]...)
+		o.midLine = p[i-1] != '\n'
+		p = p[i:]
+	}
+	if _, err := destination.Write(b); err != nil {
+		return 0, err
+	}
+	return written, nil
+}
+
+// SetLogger routes the messages the debugger prints on its own, such as
+// "< Evaluating channel expressions and RHS of send expressions. >", through l
+// so that they carry the same prefix and timestamps as the rest of the
+// program's logs. Responses to commands entered at the prompt are still written
+// to the writer set by SetOutput. Passing nil writes everything there again.
+func SetLogger(l *log.Logger) {
+	logger = l
+}
+
+// notify prints an informational message that is not a response to a command.
+// Nothing is printed if the message's verbose category is off.
+func notify(category, msg string) {
+	if !verbose(category) {
+		return
+	}
+	if l := logger; l != nil {
+		l.Println(msg)
+		return
+	}
+	fmt.Fprintln(output, msg)
+}
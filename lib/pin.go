@@ -0,0 +1,119 @@
+package godebug
+
+// This file implements "pin", which keeps the values of expressions in view
+// at every pause.
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// pins are the expressions entered with pin, in the order they were pinned.
+// They are only used while the program is paused, so they need no lock.
+var pins []string
+
+// pinRegion draws the pinned values on the top lines of the terminal, which
+// stay in place while the lines below them scroll. It is set when the
+// debugger talks to a terminal; otherwise the values are printed below the
+// line at each pause.
+var pinRegion interface {
+	draw(lines []string)
+	release()
+}
+
+// pinCommand implements "pin <expression>".
+func pinCommand(s *Scope, expr string) {
+	if expr == "" {
+		fmt.Fprintln(output, "usage: pin <expression>")
+		return
+	}
+	if _, err := evalOne(s, expr); err != nil {
+		fmt.Fprintln(output, err)
+		return
+	}
+	pins = append(pins, expr)
+	if !drawPins(s) {
+		fmt.Fprintln(output, pinLine(s, len(pins)))
+	}
+}
+
+// unpinCommand implements "unpin <n>".
+func unpinCommand(s *Scope, arg string) {
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		fmt.Fprintln(output, "usage: unpin <n>")
+		return
+	}
+	if n < 1 || n > len(pins) {
+		fmt.Fprintf(output, "There is no pin %d; info pins lists them.\n", n)
+		return
+	}
+	expr := pins[n-1]
+	pins = append(pins[:n-1], pins[n:]...)
+	fmt.Fprintf(output, "Unpinned %d, %s.\n", n, expr)
+	drawPins(s)
+}
+
+// printPins implements "info pins".
+func printPins() {
+	if len(pins) == 0 {
+		fmt.Fprintln(output, "No pins.")
+		return
+	}
+	for i, expr := range pins {
+		fmt.Fprintf(output, "%d: %s\n", i+1, expr)
+	}
+}
+
+// showPins is called when the program pauses. It redraws the pinned values
+// at the top of the terminal or, without one, prints them.
+func showPins(s *Scope) {
+	if len(pins) == 0 || drawPins(s) {
+		return
+	}
+	for i := range pins {
+		fmt.Fprintln(output, pinLine(s, i+1))
+	}
+}
+
+// drawPins redraws the pinned values at the top of the terminal, and reports
+// whether there is a terminal to draw them on. Output sent elsewhere by
+// SetOutput, SetInput or a PauseHandler gets them printed instead.
+func drawPins(s *Scope) bool {
+	if pinRegion == nil || destination != os.Stdout || promptOnOutput || pauseHandler != nil {
+		return false
+	}
+	lines := make([]string, len(pins))
+	for i := range pins {
+		// A value that spans lines would push the ones below it out of place.
+		lines[i] = strings.Join(strings.Fields(pinLine(s, i+1)), " ")
+	}
+	pinRegion.draw(lines)
+	return true
+}
+
+// releasePins lets the whole terminal scroll again when the program runs
+// freely, so that a program that ends leaves it as it was. The pinned values
+// are drawn again at the next pause.
+func releasePins() {
+	if pinRegion != nil && len(pins) > 0 {
+		pinRegion.release()
+	}
+}
+
+// pinLine formats pin n, or why it cannot be evaluated here, e.g. because
+// the pause is in a function it is not in scope in.
+func pinLine(s *Scope, n int) string {
+	expr := pins[n-1]
+	v, err := evalOne(s, expr)
+	if err != nil {
+		msg := err.Error()
+		if i := strings.IndexByte(msg, '\n'); i >= 0 {
+			msg = msg[:i]
+		}
+		return fmt.Sprintf("pin %d: %s: %s", n, expr, msg)
+	}
+	return fmt.Sprintf("pin %d: %s = %s", n, expr, valueString(expr, v))
+}
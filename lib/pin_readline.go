@@ -0,0 +1,47 @@
+// +build !js
+
+package godebug
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// terminalPins keeps the pinned values on the top rows of the terminal. The
+// rows below them are made a scrolling region of their own, so the output
+// of commands and of the program scrolls under the pins instead of over them.
+// The escape sequences go to the debugger's destination, which is still the
+// terminal while "output on" has os.Stdout writing to a pipe, and skip the
+// line numbers and the transcript.
+type terminalPins struct {
+	rows int // how many rows the pins take up, or 0 if they take up none
+}
+
+func (t *terminalPins) draw(lines []string) {
+	var b bytes.Buffer
+	b.WriteString("\x1b7") // Save the cursor; setting the region moves it.
+	if len(lines) > 0 {
+		fmt.Fprintf(&b, "\x1b[%dr", len(lines)+1)
+	} else {
+		b.WriteString("\x1b[r")
+	}
+	for i, line := range lines {
+		fmt.Fprintf(&b, "\x1b[%dH\x1b[2K%s", i+1, line)
+	}
+	// Clear the rows of pins that were removed.
+	for i := len(lines); i < t.rows; i++ {
+		fmt.Fprintf(&b, "\x1b[%dH\x1b[2K", i+1)
+	}
+	b.WriteString("\x1b8")
+	t.rows = len(lines)
+	destination.Write(b.Bytes())
+}
+
+func (t *terminalPins) release() {
+	if t.rows == 0 {
+		return
+	}
+	io.WriteString(destination, "\x1b7\x1b[r\x1b8")
+	t.rows = 0
+}
@@ -0,0 +1,35 @@
+package godebug
+
+// This file implements the pprof-labels setting, which marks the goroutine the
+// debugger follows in profiles.
+
+import (
+	gocontext "context"
+	"runtime/pprof"
+	"sync/atomic"
+)
+
+// followedLabels are the pprof labels of the goroutine the debugger follows.
+var followedLabels = pprof.WithLabels(gocontext.Background(), pprof.Labels("godebug", "followed"))
+
+// labelFollowed is called when c's goroutine pauses. With pprof-labels on, it
+// gives the goroutine followedLabels, keeping the labels it had to put back.
+func labelFollowed(c *Context) {
+	if c.g.pprofLabeled || getSetting("pprof-labels") == "off" {
+		return
+	}
+	c.g.ownLabels = getProfLabel()
+	pprof.SetGoroutineLabels(followedLabels)
+	c.g.pprofLabeled = true
+}
+
+// unlabelFollowed is called by a goroutine that has followedLabels as it runs
+// generated code. Once the debugger stops following it, or pprof-labels is
+// turned off, it gives the goroutine back the labels it had before.
+func unlabelFollowed(c *Context) {
+	if currentState != run && atomic.LoadUint32(&currentGoroutine) == c.goroutine && getSetting("pprof-labels") == "on" {
+		return
+	}
+	setProfLabel(c.g.ownLabels)
+	c.g.ownLabels, c.g.pprofLabeled = nil, false
+}
@@ -0,0 +1,591 @@
+package godebug
+
+// This file implements the "print" command and its format modifiers.
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/mailgun/godebug/Godeps/_workspace/src/github.com/0xfaded/eval"
+)
+
+// printFormats lists the modifiers that may follow "print/" or "p/".
+var printFormats = map[string]bool{
+	"":      true,
+	"flags": true,
+	"json":  true,
+	"s":     true,
+	"x":     true,
+}
+
+// evalValue evaluates expr, which must have a single value. If it does not, or
+// if evaluating it fails, evalValue prints why and ok is false.
+func evalValue(scope *Scope, expr string) (v reflect.Value, ok bool) {
+	v, err := evalOne(scope, expr)
+	if err != nil {
+		fmt.Fprintln(output, err)
+		return reflect.Value{}, false
+	}
+	return v, true
+}
+
+// evalOne evaluates expr, which must have a single value.
+func evalOne(scope *Scope, expr string) (reflect.Value, error) {
+	results, panik, compileErrs := goEval(expr, scope)
+	switch {
+	case compileErrs != nil:
+		msgs := make([]string, len(compileErrs))
+		for i, err := range compileErrs {
+			msgs[i] = err.Error()
+		}
+		return reflect.Value{}, errors.New(strings.Join(msgs, "\n"))
+	case panik != nil:
+		return reflect.Value{}, fmt.Errorf("panic (recovered): %v", panik)
+	case len(results) == 2 && isIndexExpr(expr):
+		// The evaluator gives a map index its "comma ok" form.
+		return results[0], nil
+	case len(results) != 1:
+		return reflect.Value{}, fmt.Errorf("%s has %d values; expected 1", expr, len(results))
+	}
+	return results[0], nil
+}
+
+// evalEnv is eval.EvalEnv, with fixes to how the evaluator runs an
+// expression once it has been checked.
+//
+// The right side of && and || is only evaluated when the left side does not
+// decide the result, as in Go. The evaluator evaluates both, so
+// "p != nil && p.n > 0" would fail when p is nil, which is the very case such
+// a condition is written for.
+//
+// Selecting a field through a nil pointer, as in user.Address.City when
+// Address is nil, says which pointer is nil, where the evaluator panics in
+// reflect.
+//
+// Indexing a map gives only the value, as m[key] does in Go, where the
+// evaluator also gives whether the key was there. An index out of range says
+// what the index and the length were.
+//
+// These fixes apply to the expression and to the operands of &&, ||,
+// selectors and indexes in it, as in a != nil && a.users[id].Tags[0] == "x".
+// Inside other operations, as in len(users[id].Tags), the evaluator handles
+// them itself.
+func evalEnv(expr string, env eval.Env) (result []reflect.Value, panik error, compileErrors []error) {
+	e, err := parser.ParseExpr(expr)
+	if err != nil {
+		errs := err.(scanner.ErrorList)
+		for i := range errs {
+			compileErrors = append(compileErrors, errs[i])
+		}
+		return nil, nil, compileErrors
+	}
+	checked, errs := eval.CheckExpr(e, env)
+	if errs != nil {
+		return nil, nil, errs
+	}
+	result, panik = evalChecked(checked, env)
+	return result, panik, nil
+}
+
+// evalChecked evaluates the checked expression e as evalEnv describes.
+func evalChecked(e eval.Expr, env eval.Env) ([]reflect.Value, error) {
+	switch x := e.(type) {
+	case *eval.ParenExpr:
+		return evalChecked(x.X, env)
+	case *eval.BinaryExpr:
+		if op := x.Op(); !x.IsConst() && (op == token.LAND || op == token.LOR) {
+			left, err := evalChecked(x.X, env)
+			if err != nil || len(left) != 1 || left[0].Kind() != reflect.Bool {
+				break
+			}
+			if left[0].Bool() == (op == token.LOR) {
+				return []reflect.Value{reflect.ValueOf(left[0].Bool())}, nil
+			}
+			right, err := evalChecked(x.Y, env)
+			if err != nil || len(right) != 1 || right[0].Kind() != reflect.Bool {
+				return right, err
+			}
+			return []reflect.Value{reflect.ValueOf(right[0].Bool())}, nil
+		}
+	case *eval.SelectorExpr:
+		if field, ok := selectedField(x); ok {
+			return evalField(x, field, env)
+		}
+	case *eval.IndexExpr:
+		if !x.IsConst() {
+			return evalIndex(x, env)
+		}
+	}
+	return eval.EvalExpr(e, env)
+}
+
+// selectedField returns the struct field x selects, if it selects a field
+// rather than a method or something in a package.
+func selectedField(x *eval.SelectorExpr) (reflect.StructField, bool) {
+	types := x.X.KnownType()
+	if x.IsConst() || len(types) != 1 || types[0] == nil {
+		return reflect.StructField{}, false
+	}
+	t := types[0]
+	if _, ok := t.(eval.ConstType); ok {
+		return reflect.StructField{}, false
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return reflect.StructField{}, false
+	}
+	return t.FieldByName(x.Sel.Name)
+}
+
+// evalField selects field from the value of x.X, following pointers on the
+// way, including those to embedded structs the field is promoted from.
+func evalField(x *eval.SelectorExpr, field reflect.StructField, env eval.Env) ([]reflect.Value, error) {
+	vs, err := evalChecked(x.X, env)
+	if err != nil {
+		return nil, err
+	}
+	v, path := vs[0], x.X.String()
+	for _, index := range field.Index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return nil, fmt.Errorf("%s is nil", path)
+			}
+			v = v.Elem()
+		}
+		path += "." + v.Type().Field(index).Name
+		v = v.Field(index)
+	}
+	return []reflect.Value{v}, nil
+}
+
+// evalIndex evaluates x, an index into a map, slice, array, pointer to an
+// array or string.
+func evalIndex(x *eval.IndexExpr, env eval.Env) ([]reflect.Value, error) {
+	vs, err := evalChecked(x.X, env)
+	if err != nil {
+		return nil, err
+	}
+	is, err := evalChecked(x.Index, env)
+	if err != nil {
+		return nil, err
+	}
+	v, index := vs[0], is[0]
+	if v.Kind() == reflect.Map {
+		key, err := mapKey(index, v.Type().Key(), x.Index.IsConst())
+		if err != nil {
+			return nil, err
+		}
+		e := v.MapIndex(key)
+		if !e.IsValid() {
+			e = reflect.Zero(v.Type().Elem())
+		}
+		return []reflect.Value{e}, nil
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, fmt.Errorf("%s is nil", x.X)
+		}
+		v = v.Elem()
+	}
+	i, err := assignable(index, reflect.TypeOf(0))
+	if err != nil {
+		return nil, err
+	}
+	if n := int(i.Int()); n < 0 || n >= v.Len() {
+		return nil, fmt.Errorf("runtime error: index out of range [%d] with length %d", n, v.Len())
+	}
+	return []reflect.Value{v.Index(int(i.Int()))}, nil
+}
+
+// mapKey returns k as a key of type t. A constant key, like "name", is
+// converted to t if it has t's kind, as the compiler would for a map whose
+// keys are of a named string type.
+func mapKey(k reflect.Value, t reflect.Type, constant bool) (reflect.Value, error) {
+	k, ok := accessible(k)
+	if !ok {
+		return k, fmt.Errorf("godebug cannot use %s value as a key", k.Type())
+	}
+	if constant {
+		switch k.Interface().(type) {
+		case eval.UntypedNil, *eval.ConstNumber:
+			return assignable(k, t)
+		}
+		if k.Kind() == t.Kind() && k.Type().ConvertibleTo(t) {
+			return k.Convert(t), nil
+		}
+	}
+	return assignable(k, t)
+}
+
+func isIndexExpr(expr string) bool {
+	e, err := parser.ParseExpr(expr)
+	if err != nil {
+		return false
+	}
+	_, ok := e.(*ast.IndexExpr)
+	return ok
+}
+
+// Eval evaluates a Go expression in s, the way the print command does: names
+// in scope, fields, indexing, built-ins like len, arithmetic and calls to
+// exported methods are all allowed. The expression must have a single value.
+// Unlike print, Eval does not redact secrets.
+//
+// Eval is meant for a PauseHandler, which finds the paused scope in its
+// Snapshot. Calling it while the program is running races with the program.
+func (s *Scope) Eval(expr string) (interface{}, error) {
+	v, err := evalOne(s, expr)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := accessible(v)
+	if !ok {
+		return nil, fmt.Errorf("godebug cannot access %s", expr)
+	}
+	return v.Interface(), nil
+}
+
+// accessible returns v, or if v was reached through an unexported field, a
+// value for the same memory that can be turned back into an interface.
+func accessible(v reflect.Value) (reflect.Value, bool) {
+	if v.CanInterface() {
+		return v, true
+	}
+	if !v.CanAddr() {
+		return v, false
+	}
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem(), true
+}
+
+func printExpr(scope *Scope, expr, format string) {
+	if !printFormats[format] {
+		fmt.Fprintf(output, "unknown print format %q\n", format)
+		return
+	}
+	results, panik, compileErrs := goEval(expr, scope)
+	switch {
+	case compileErrs != nil:
+		for _, err := range compileErrs {
+			fmt.Fprintln(output, err)
+		}
+	case panik != nil:
+		fmt.Fprintf(output, "panic (recovered): %v\n", panik)
+	case len(results) == 0:
+		// A call to a function with no results, e.g. "p buf.Reset()".
+	default:
+		s := make([]string, len(results))
+		for i, r := range results {
+			s[i] = formatResult(expr, r, format, scope)
+		}
+		fmt.Fprintln(output, strings.Join(s, ", "))
+	}
+}
+
+func formatResult(expr string, r reflect.Value, format string, scope *Scope) string {
+	r, ok := accessible(r)
+	if !ok {
+		return "godebug cannot access this field or method. Sorry! Let us know about it at github.com/mailgun/godebug/issues/new and we'll fix it"
+	}
+	ifc := redact(expr, r.Interface())
+	switch format {
+	case "flags":
+		if s, ok := formatFlags(ifc, scope); ok {
+			return s
+		}
+	case "s", "x":
+		if s, ok := formatBytes(ifc, format); ok {
+			return s
+		}
+	case "json":
+		return formatJSON(ifc)
+	}
+	if r.Kind() != reflect.Interface || r.IsNil() {
+		return formatValue(ifc)
+	}
+	if isTypedNil(r) {
+		if getSetting("print-static-type") == "off" {
+			return formatValue(ifc)
+		}
+		return fmt.Sprintf("%s(%s)", r.Type(), formatValue(ifc))
+	}
+	s := withType(reflect.TypeOf(ifc), formatValue(ifc))
+	if getSetting("print-static-type") == "off" {
+		return s
+	}
+	return fmt.Sprintf("%s = %s", r.Type(), s)
+}
+
+// withType writes s, the formatted value of a dynamic type t held by an
+// interface, as a conversion to t, unless s already begins with its type as
+// composite literals do.
+func withType(t reflect.Type, s string) string {
+	name := t.String()
+	if strings.HasPrefix(s, name) || strings.HasPrefix(s, "("+name+")") {
+		return s
+	}
+	if t.Kind() == reflect.Ptr && strings.HasPrefix(s, "&"+t.Elem().String()) {
+		return s
+	}
+	return fmt.Sprintf("%s(%s)", name, s)
+}
+
+// formatJSON renders i for print/json, as encoding/json marshals it. Like
+// json.Marshal, it leaves out unexported fields. If i cannot be marshaled, for
+// example because it holds a channel, formatJSON gives the usual rendering
+// followed by why.
+func formatJSON(i interface{}) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(i); err != nil {
+		return fmt.Sprintf("%s (not JSON: %v)", formatValue(i), err)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// isTypedNil reports whether v is an interface holding a nil pointer, map,
+// slice, func or channel. Such an interface is not nil, which print shows by
+// writing it as a conversion, like error((*main.myErr)(nil)), where a nil
+// interface is just <nil>.
+func isTypedNil(v reflect.Value) bool {
+	if v.Kind() != reflect.Interface || v.IsNil() {
+		return false
+	}
+	switch e := v.Elem(); e.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return e.IsNil()
+	}
+	return false
+}
+
+// formatValue renders a value the way print shows it by default, cut short
+// after print-maxbytes bytes.
+func formatValue(i interface{}) string {
+	max, _ := strconv.Atoi(getSetting("print-maxbytes"))
+	s := formatUnlimited(i, max)
+	if max > 0 && len(s) > max {
+		for max > 0 && !utf8.RuneStart(s[max]) {
+			max--
+		}
+		s = s[:max] + "... (truncated)"
+	}
+	return s
+}
+
+// formatUnlimited renders i. If max is positive, it may stop early once the
+// result is longer than max bytes.
+func formatUnlimited(i interface{}, max int) string {
+	if _, ok := i.(*eval.ConstNumber); ok {
+		return fmt.Sprintf("%v", i)
+	}
+	if getSetting("print-gosyntax") == "strict" {
+		return goSyntax(reflect.ValueOf(i), max)
+	}
+	if getSetting("print-time") == "readable" {
+		switch t := i.(type) {
+		case time.Duration:
+			return t.String()
+		case time.Time:
+			return t.String()
+		}
+	}
+	if v := reflect.ValueOf(i); v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 && isText(v.Bytes()) {
+		name := v.Type().String()
+		if v.Type() == reflect.TypeOf([]byte(nil)) {
+			name = "[]byte" // rather than []uint8
+		}
+		return fmt.Sprintf("%s(%q)", name, v.Bytes())
+	}
+	return fmt.Sprintf("%#v", i)
+}
+
+// isText reports whether b is non-empty, printable UTF-8, so that print can
+// show it as a string rather than a list of bytes.
+func isText(b []byte) bool {
+	if len(b) == 0 || !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if !unicode.IsPrint(r) && r != '\n' && r != '\t' && r != '\r' {
+			return false
+		}
+	}
+	return true
+}
+
+// formatBytes renders a byte slice, byte array or string for print/s, as a
+// quoted string, or print/x, as a hex dump. print/x also writes integers in
+// hex. Only the first print-maxbytes bytes are shown. ok is false if i is
+// none of these.
+func formatBytes(i interface{}, format string) (s string, ok bool) {
+	v := reflect.ValueOf(i)
+	var b []byte
+	switch {
+	case v.Kind() == reflect.String:
+		b = []byte(v.String())
+	case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() == reflect.Uint8:
+		b = make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(b), v)
+	case format == "x":
+		if _, isConst := i.(*eval.ConstNumber); isConst {
+			if n, ok := flagBits(i); ok {
+				return fmt.Sprintf("%#x", n), true
+			}
+			return "", false
+		}
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return fmt.Sprintf("%#x", i), true
+		}
+		return "", false
+	default:
+		return "", false
+	}
+	more := ""
+	if max, _ := strconv.Atoi(getSetting("print-maxbytes")); max > 0 && len(b) > max {
+		more = fmt.Sprintf("... (%d more bytes)", len(b)-max)
+		b = b[:max]
+	}
+	if format == "s" {
+		return strconv.Quote(string(b)) + more, true
+	}
+	if len(b) == 0 {
+		return "(no bytes)", true
+	}
+	dump := strings.TrimSuffix(hex.Dump(b), "\n")
+	if more != "" {
+		dump += "\n" + more
+	}
+	return dump, true
+}
+
+type flag struct {
+	name string
+	bits uint64
+}
+
+// formatFlags renders an integer as the bitwise OR of the integer constants
+// visible from scope that make it up, e.g. "O_WRONLY|O_CREATE". Bits that no
+// constant accounts for are appended in hex. ok is false if i is not an
+// integer or if no constant matches.
+func formatFlags(i interface{}, scope *Scope) (s string, ok bool) {
+	n, ok := flagBits(i)
+	if !ok {
+		return "", false
+	}
+	var candidates []flag
+	seen := make(map[string]bool)
+	for sc := scope; sc != nil; sc = sc.parent {
+		for name, c := range sc.Consts {
+			// Inner scopes shadow outer ones, as in getIdent.
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if bits, ok := flagBits(c); ok && bits&n == bits && (bits != 0 || n == 0) {
+				candidates = append(candidates, flag{name, bits})
+			}
+		}
+	}
+	if n == 0 {
+		if len(candidates) == 0 {
+			return "", false
+		}
+		sort.Sort(flagsByName(candidates))
+		return candidates[0].name, true
+	}
+
+	// Try constants that cover more bits first, so that a mask like O_RDWR
+	// is preferred over the flags it is made of.
+	sort.Sort(flagsByWidth(candidates))
+	var chosen []flag
+	rest := n
+	for _, f := range candidates {
+		if f.bits&rest == f.bits {
+			chosen = append(chosen, f)
+			rest &^= f.bits
+		}
+	}
+	if len(chosen) == 0 {
+		return "", false
+	}
+	sort.Sort(flagsByValue(chosen))
+	names := make([]string, 0, len(chosen)+1)
+	for _, f := range chosen {
+		names = append(names, f.name)
+	}
+	if rest != 0 {
+		names = append(names, fmt.Sprintf("%#x", rest))
+	}
+	return strings.Join(names, "|"), true
+}
+
+// flagBits returns the bits of a non-negative integer value.
+func flagBits(i interface{}) (bits uint64, ok bool) {
+	if c, isConst := i.(*eval.ConstNumber); isConst {
+		u, truncation, overflow := c.Value.Uint(64)
+		return u, !truncation && !overflow
+	}
+	v := reflect.ValueOf(i)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() < 0 {
+			return 0, false
+		}
+		return uint64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint(), true
+	}
+	return 0, false
+}
+
+func popcount(n uint64) (count int) {
+	for ; n != 0; n &= n - 1 {
+		count++
+	}
+	return count
+}
+
+type flagsByName []flag
+
+func (f flagsByName) Len() int           { return len(f) }
+func (f flagsByName) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
+func (f flagsByName) Less(i, j int) bool { return f[i].name < f[j].name }
+
+type flagsByWidth []flag
+
+func (f flagsByWidth) Len() int      { return len(f) }
+func (f flagsByWidth) Swap(i, j int) { f[i], f[j] = f[j], f[i] }
+func (f flagsByWidth) Less(i, j int) bool {
+	if wi, wj := popcount(f[i].bits), popcount(f[j].bits); wi != wj {
+		return wi > wj
+	}
+	if f[i].bits != f[j].bits {
+		return f[i].bits < f[j].bits
+	}
+	return f[i].name < f[j].name
+}
+
+type flagsByValue []flag
+
+func (f flagsByValue) Len() int           { return len(f) }
+func (f flagsByValue) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
+func (f flagsByValue) Less(i, j int) bool { return f[i].bits < f[j].bits }
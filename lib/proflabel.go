@@ -0,0 +1,15 @@
+// +build !js
+
+package godebug
+
+// runtime/pprof can set a goroutine's labels but not read them back, so the
+// debugger gets at the runtime's copy directly. The pointer is only handed
+// back to setProfLabel, never looked into.
+
+import "unsafe"
+
+//go:linkname getProfLabel runtime/pprof.runtime_getProfLabel
+func getProfLabel() unsafe.Pointer
+
+//go:linkname setProfLabel runtime/pprof.runtime_setProfLabel
+func setProfLabel(labels unsafe.Pointer)
@@ -0,0 +1,11 @@
+// +build js
+
+package godebug
+
+// gopherjs has no profiler, so goroutines have no labels to keep.
+
+import "unsafe"
+
+func getProfLabel() unsafe.Pointer { return nil }
+
+func setProfLabel(labels unsafe.Pointer) {}
@@ -0,0 +1,113 @@
+package godebug
+
+// This file implements the "record" command and SetInput, which together let
+// a session be recorded and replayed, as godebugtest.Replay does.
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// recording is the file "record" copies the session to, or nil.
+var recording struct {
+	sync.Mutex
+	f *os.File
+
+	// paused is set while the record command runs, whose own output would
+	// not be there to compare with when the recording is replayed.
+	paused bool
+}
+
+// recordCommand implements "record <file>" and "record off". The recording
+// starts with the line the debugger is paused at, so that a recording started
+// at the first pause can be replayed against a new run of the program.
+func recordCommand(s *Scope, line int, args string) {
+	setRecordingPaused(true)
+	defer setRecordingPaused(false)
+	switch args {
+	case "":
+		fmt.Fprintln(output, "usage: record <file> or record off")
+	case "off":
+		recording.Lock()
+		f := recording.f
+		recording.f = nil
+		recording.Unlock()
+		if f == nil {
+			fmt.Fprintln(output, "Not recording.")
+			return
+		}
+		if err := f.Close(); err != nil {
+			fmt.Fprintln(output, err)
+		}
+		fmt.Fprintf(output, "Stopped recording to %s.\n", f.Name())
+	default:
+		recording.Lock()
+		old := recording.f
+		recording.Unlock()
+		if old != nil {
+			fmt.Fprintf(output, "Already recording to %s.\n", old.Name())
+			return
+		}
+		f, err := os.Create(args)
+		if err != nil {
+			fmt.Fprintln(output, err)
+			return
+		}
+		fmt.Fprintf(output, "Recording to %s.\n", args)
+		fmt.Fprintln(f, getSetting("line-prefix")+s.sourceLine(line))
+		recording.Lock()
+		recording.f = f
+		recording.Unlock()
+	}
+}
+
+func setRecordingPaused(paused bool) {
+	recording.Lock()
+	recording.paused = paused
+	recording.Unlock()
+}
+
+// record copies output to the recording, if there is one.
+func record(p []byte) {
+	recording.Lock()
+	defer recording.Unlock()
+	if recording.f != nil && !recording.paused {
+		recording.f.Write(p)
+	}
+}
+
+// recordInput adds a command to the recording the way a transcript shows it.
+func recordInput(cmd string) {
+	record([]byte("(godebug) " + cmd + "\n"))
+}
+
+// echoInput is set while commands come from a reader passed to SetInput.
+var (
+	echoInput     bool
+	terminalInput func() (response string, ok bool)
+)
+
+// SetInput makes the debugger read commands from r, one per line, instead of
+// from the terminal. The prompt goes to the output, followed by each command
+// as a terminal would show it, so that the output reads as a transcript like
+// the ones the record command makes. Passing nil reads from the terminal again.
+//
+// Like SetPauseHandler, SetInput is not safe to call while the debugger is
+// paused.
+func SetInput(r io.Reader) {
+	if terminalInput == nil {
+		terminalInput = promptUser
+	}
+	if r == nil {
+		input = bufio.NewScanner(os.Stdin)
+		promptUser = terminalInput
+		echoInput = false
+		return
+	}
+	input = bufio.NewScanner(r)
+	promptUser = fallbackPrompt
+	echoInput = true
+}
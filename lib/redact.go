@@ -0,0 +1,121 @@
+package godebug
+
+// This file implements SetRedactor and the redactor that hides secrets.
+
+import (
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+var redactor func(name string, v interface{}) interface{}
+
+// SetRedactor installs f to transform values before the debugger shows them.
+// print, inspect, info locals, diff and equal pass each value they display
+// through f along with its name, which is the variable name, field name or
+// expression as it was typed. f returns the value to show in its place, so it
+// can hide secrets from the screen and from logs. Passing nil shows values as
+// they are again.
+//
+// RedactSecrets is a ready-made redactor.
+func SetRedactor(f func(name string, v interface{}) interface{}) {
+	redactor = f
+}
+
+// redact applies the redactor set by SetRedactor, if any, to v.
+func redact(name string, v interface{}) interface{} {
+	if f := redactor; f != nil {
+		return f(name, v)
+	}
+	return v
+}
+
+// secretWords are the parts of names that RedactSecrets treats as secrets.
+var secretWords = []string{"password", "passwd", "secret", "token", "apikey", "credential"}
+
+// Redacted replaces the values that RedactSecrets hides.
+const Redacted = "[redacted]"
+
+// RedactSecrets is a redactor for SetRedactor. It hides values whose names
+// look like they hold secrets, such as "password", "authToken" or
+// "user.APIKey". In structs, and structs pointed to, it hides the fields with
+// such names, exported or not. Hidden strings are replaced with Redacted and
+// other hidden values with their zero value. The program's own values are
+// never changed; RedactSecrets works on copies.
+func RedactSecrets(name string, v interface{}) interface{} {
+	if isSecretName(name) {
+		return Redacted
+	}
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || !hasSecretFields(rv.Type(), maxCopyDepth) {
+		return v
+	}
+	return redactCopy(rv, maxCopyDepth).Interface()
+}
+
+// isSecretName reports whether the last part of a name or expression like
+// "u.Password" names a secret.
+func isSecretName(name string) bool {
+	if i := strings.LastIndexAny(name, ".["); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.ToLower(strings.Replace(name, "_", "", -1))
+	for _, w := range secretWords {
+		if strings.Contains(name, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSecretFields reports whether values of type t have secret fields that
+// redactCopy would hide.
+func hasSecretFields(t reflect.Type, depth int) bool {
+	if depth == 0 {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return hasSecretFields(t.Elem(), depth-1)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if isSecretName(t.Field(i).Name) || hasSecretFields(t.Field(i).Type, depth-1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactCopy returns a copy of v, a struct or pointer to one, with its secret
+// fields hidden.
+func redactCopy(v reflect.Value, depth int) reflect.Value {
+	if depth == 0 || !hasSecretFields(v.Type(), depth) {
+		return v
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return v
+		}
+		p := reflect.New(v.Type().Elem())
+		p.Elem().Set(redactCopy(v.Elem(), depth-1))
+		return p
+	}
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	for i := 0; i < c.NumField(); i++ {
+		// Fields of an addressable struct can be set through their address,
+		// even if they are unexported.
+		f := c.Field(i)
+		f = reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+		switch {
+		case isSecretName(c.Type().Field(i).Name) && f.Kind() == reflect.String:
+			f.SetString(Redacted)
+		case isSecretName(c.Type().Field(i).Name):
+			f.Set(reflect.Zero(f.Type()))
+		default:
+			f.Set(redactCopy(f, depth-1))
+		}
+	}
+	return c
+}
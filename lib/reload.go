@@ -0,0 +1,31 @@
+package godebug
+
+// This file implements the "reload" command.
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// reloadSource reads the file s belongs to from disk again, so that list and
+// the line shown at each pause reflect edits made since the program was built.
+// The code that runs is not affected.
+func reloadSource(s *Scope) {
+	f := s.file
+	if f.name == "" {
+		fmt.Fprintln(output, "godebug did not record which file this code came from. Generate it again with a newer godebug to use reload.")
+		return
+	}
+	b, err := ioutil.ReadFile(f.name)
+	if err != nil {
+		fmt.Fprintln(output, err)
+		return
+	}
+	lines := parseLines(strings.Replace(string(b), "\r\n", "\n", -1))
+	if old := f.text(); len(lines) != len(old) {
+		fmt.Fprintf(output, "Warning: %s had %d lines and now has %d, so line numbers may not match the code that is running.\n", f.name, len(old), len(lines))
+	}
+	f.setText(lines)
+	fmt.Fprintf(output, "Reloaded %s.\n", f.name)
+}
@@ -0,0 +1,109 @@
+package godebug
+
+// This file implements "info select", which says which cases of the select
+// statement the program is paused at could proceed.
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+)
+
+// printSelectInfo implements "info select". The channels are found by
+// evaluating the expressions in the source again, so a channel expression that
+// calls a function is not checked rather than run twice.
+func printSelectInfo(s *Scope, line int) {
+	// Parse the code that is running, not text from reload or a source provider.
+	text := strings.Join(s.file.embedded, "\n")
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", text, 0)
+	if err != nil {
+		fmt.Fprintln(output, "The source of this file cannot be parsed:", err)
+		return
+	}
+	sel := findSelect(fset, f, line)
+	if sel == nil {
+		fmt.Fprintln(output, "Not paused at a select statement.")
+		return
+	}
+	for _, stmt := range sel.Body.List {
+		clause := stmt.(*ast.CommClause)
+		header := text[fset.Position(clause.Pos()).Offset : fset.Position(clause.Colon).Offset+1]
+		fmt.Fprintf(output, "%s %s\n", header, caseReadiness(s, text, fset, clause.Comm))
+	}
+}
+
+// findSelect returns the innermost select statement whose "select" keyword or
+// case clauses are on the given line.
+func findSelect(fset *token.FileSet, f *ast.File, line int) (sel *ast.SelectStmt) {
+	ast.Inspect(f, func(n ast.Node) bool {
+		s, ok := n.(*ast.SelectStmt)
+		if !ok {
+			return true
+		}
+		if fset.Position(s.Select).Line == line {
+			sel = s
+		}
+		for _, stmt := range s.Body.List {
+			if fset.Position(stmt.Pos()).Line == line {
+				sel = s
+			}
+		}
+		return true
+	})
+	return sel
+}
+
+// caseReadiness describes whether the communication of a case could proceed
+// right now, without performing it.
+func caseReadiness(s *Scope, text string, fset *token.FileSet, comm ast.Stmt) string {
+	var ch ast.Expr
+	send := false
+	switch comm := comm.(type) {
+	case nil:
+		return "runs if no other case is ready"
+	case *ast.SendStmt:
+		ch, send = comm.Chan, true
+	case *ast.ExprStmt:
+		ch = comm.X.(*ast.UnaryExpr).X
+	case *ast.AssignStmt:
+		ch = comm.Rhs[0].(*ast.UnaryExpr).X
+	}
+	if hasCall(ch) {
+		return "not checked; evaluating the channel would call a function"
+	}
+	expr := text[fset.Position(ch.Pos()).Offset:fset.Position(ch.End()).Offset]
+	results, panik, compileErrs := goEval(expr, s)
+	if compileErrs != nil || panik != nil || len(results) != 1 || results[0].Kind() != reflect.Chan {
+		return "not checked; the channel cannot be evaluated here"
+	}
+	v := results[0]
+	switch {
+	case v.IsNil():
+		return "never ready (nil channel)"
+	case v.Cap() == 0 && send:
+		return "ready only if a receiver is waiting (unbuffered)"
+	case v.Cap() == 0:
+		return "ready only if a sender is waiting or the channel is closed (unbuffered)"
+	case send && v.Len() < v.Cap():
+		return fmt.Sprintf("ready (%d of %d buffered)", v.Len(), v.Cap())
+	case send:
+		return fmt.Sprintf("blocked (buffer full, %d of %d)", v.Len(), v.Cap())
+	case v.Len() > 0:
+		return fmt.Sprintf("ready (%d of %d buffered)", v.Len(), v.Cap())
+	}
+	return fmt.Sprintf("blocked unless the channel is closed (0 of %d buffered)", v.Cap())
+}
+
+func hasCall(e ast.Expr) (found bool) {
+	ast.Inspect(e, func(n ast.Node) bool {
+		if _, ok := n.(*ast.CallExpr); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
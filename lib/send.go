@@ -0,0 +1,67 @@
+package godebug
+
+// This file shows the values that channel sends on the paused line are about
+// to send.
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// notifySends says what each channel send on the line is about to send, e.g.
+// "< sending job = 3 on jobs >". Like printSelectInfo, it evaluates the
+// expressions in the source again, so values that would take a function call
+// or a receive to compute are not shown, and neither are literals and
+// constants like true, which can be read off the line.
+func notifySends(s *Scope, line int) {
+	if !verbose("send") || !strings.Contains(s.sourceLine(line), "<-") {
+		return
+	}
+	// Parse the code that is running, not text from reload or a source provider.
+	text := strings.Join(s.file.embedded, "\n")
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", text, 0)
+	if err != nil {
+		return
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		send, ok := n.(*ast.SendStmt)
+		if !ok || fset.Position(send.Pos()).Line != line {
+			return true
+		}
+		if _, ok := send.Value.(*ast.BasicLit); ok || !sideEffectFree(send.Value) {
+			return true
+		}
+		source := func(e ast.Expr) string {
+			return text[fset.Position(e.Pos()).Offset:fset.Position(e.End()).Offset]
+		}
+		expr := source(send.Value)
+		v, err := evalOne(s, expr)
+		if err != nil {
+			return true
+		}
+		if value := valueString(expr, v); value != expr {
+			notify("send", fmt.Sprintf("< sending %s = %s on %s >", expr, value, source(send.Chan)))
+		}
+		return true
+	})
+}
+
+// sideEffectFree reports whether evaluating e again cannot change the state
+// of the program: it calls no functions and receives from no channels.
+func sideEffectFree(e ast.Expr) bool {
+	if hasCall(e) {
+		return false
+	}
+	free := true
+	ast.Inspect(e, func(n ast.Node) bool {
+		if u, ok := n.(*ast.UnaryExpr); ok && u.Op == token.ARROW {
+			free = false
+		}
+		return free
+	})
+	return free
+}
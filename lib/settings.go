@@ -0,0 +1,299 @@
+package godebug
+
+// This file implements the "set" command.
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A setting is a debugger option that can be changed with "set <name> <value>".
+// If allowed is nil, the setting takes any string accepted by validate, which
+// may be quoted as a Go string literal to include spaces.
+type setting struct {
+	value    string
+	allowed  []string
+	validate func(string) error
+	help     string
+}
+
+var settings = map[string]*setting{
+	"print-gosyntax": {
+		value:   "off",
+		allowed: []string{"off", "strict"},
+		help:    `"strict" prints values as Go literals that compile where possible.`,
+	},
+	"line-prefix": {
+		value: "-> ",
+		help:  "is printed before the line the debugger paused at.",
+	},
+	"banner": {
+		value:   "default",
+		allowed: []string{"default", "compact"},
+		help:    `is how the line the debugger paused at is shown. "compact" shows it on one line with its file, line number, goroutine and call depth, like "main.go:42 g1 d3 | x := f()", for logs.`,
+	},
+	"marker": {
+		value: "--> ",
+		help:  "marks the current line in list.",
+	},
+	"context-marker": {
+		value: "    ",
+		help:  "is printed before the other lines in list.",
+	},
+	"print-maxbytes": {
+		value:    "0",
+		validate: validateCount,
+		help:     "cuts values printed by print, info locals and diff short after this many bytes; 0 means no limit.",
+	},
+	"print-static-type": {
+		value:   "on",
+		allowed: []string{"on", "off"},
+		help:    `"on" prefixes a printed interface value with the interface type, as in "io.Reader = &os.File{...}"; the type of the value it holds is always shown.`,
+	},
+	"print-time": {
+		value:   "readable",
+		allowed: []string{"readable", "raw"},
+		help:    `"readable" prints time.Duration and time.Time values with their String method.`,
+	},
+	"depth-warning": {
+		value:    "1000",
+		validate: validateCount,
+		help:     "is the call depth above which info depth flags a goroutine; 0 turns the warning off.",
+	},
+	"echo": {
+		value:   "off",
+		allowed: []string{"off", "on"},
+		help:    `"on" writes each command entered to the output before its result.`,
+	},
+	"line-numbers": {
+		value:   "off",
+		allowed: []string{"off", "on"},
+		help:    `"on" starts each line the debugger prints with a sequence number, to refer to later.`,
+	},
+	"verbose": {
+		value:   "on",
+		allowed: []string{"on", "off"},
+		help:    `"off" silences the < ... > notices, such as the ones around select statements, and the deferred function marker. "set verbose <category> on|off" changes one category; see info verbose.`,
+	},
+	"follow-spawn": {
+		value:   "off",
+		allowed: []string{"off", "on"},
+		help:    `"on" makes step at a go statement pause in the new goroutine.`,
+	},
+	"autolist": {
+		value:   "off",
+		allowed: []string{"off", "on"},
+		help:    `"on" shows the code around the line at every pause, as list does.`,
+	},
+	"watchdog": {
+		value:    "off",
+		validate: validateWatchdog,
+		help:     "is how long the followed goroutine may go without running a line while stepping before the debugger says it appears blocked, e.g. 5s.",
+	},
+	"pprof-labels": {
+		value:   "off",
+		allowed: []string{"off", "on"},
+		help:    `"on" gives the goroutine the debugger pauses in the pprof label godebug=followed, in place of its own labels, until the debugger stops following it.`,
+	},
+	"on-quit": {
+		value:   "default",
+		allowed: []string{"default", "continue", "exit"},
+		help:    `is what quit and the end of input do: "default" exits the program at quit and lets it run on at the end of input, "continue" lets it run on at both, and "exit" exits at both.`,
+	},
+	"step-filter": {
+		value:    "off",
+		validate: validateStepFilter,
+		help:     `is a regular expression; step and next pause only at lines whose source matches it, e.g. "return|err". "off" pauses at every line.`,
+	},
+	"dry-run": {
+		value:   "off",
+		allowed: []string{"off", "on"},
+		help:    `"on" makes breakpoints, watches and catch print where and why they would pause the program, and let it run on. Step and next still pause.`,
+	},
+	"trace-policy": {
+		value:   "first",
+		allowed: []string{"first", "queue", "prompt"},
+		help:    `is what happens when a goroutine reaches a breakpoint while the debugger follows another: "first" ignores it, "queue" follows it at the next continue, oldest first, and "prompt" asks at the next continue which of the waiting goroutines to follow.`,
+	},
+	"goroutine-ids": {
+		value:   "reuse",
+		allowed: []string{"reuse", "sequential"},
+		help:    `"sequential" never reuses goroutine ids, so a run of the same program gives the same ids.`,
+	},
+}
+
+// settingsMu guards the values of settings, which may be read by any goroutine.
+var settingsMu sync.RWMutex
+
+// getSetting returns the current value of the named setting.
+func getSetting(name string) string {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return settings[name].value
+}
+
+func (s *setting) set(value string) {
+	settingsMu.Lock()
+	s.value = value
+	settingsMu.Unlock()
+}
+
+func setCommand(arg string) {
+	var args []string
+	if arg = strings.TrimSpace(arg); arg != "" {
+		args = strings.SplitN(arg, " ", 2)
+		if len(args) == 2 {
+			args[1] = strings.TrimSpace(args[1])
+		}
+	}
+	if len(args) == 0 {
+		for _, name := range sortedSettings() {
+			s := settings[name]
+			if s.allowed == nil {
+				fmt.Fprintf(output, "%s = %q %s\n", name, getSetting(name), s.help)
+			} else {
+				fmt.Fprintf(output, "%s = %s (%s) %s\n", name, getSetting(name), strings.Join(s.allowed, "|"), s.help)
+			}
+		}
+		return
+	}
+	if args[0] == "verbose" && len(args) == 2 && strings.Contains(args[1], " ") {
+		setVerboseCategory(strings.Fields(args[1]))
+		return
+	}
+	s, ok := settings[args[0]]
+	if !ok {
+		fmt.Fprintf(output, "unknown setting %q\n", args[0])
+		return
+	}
+	if s.allowed == nil {
+		if len(args) != 2 {
+			fmt.Fprintf(output, "usage: set %s <string>\n", args[0])
+			return
+		}
+		v := args[1]
+		if strings.HasPrefix(v, `"`) || strings.HasPrefix(v, "`") {
+			var err error
+			if v, err = strconv.Unquote(v); err != nil {
+				fmt.Fprintf(output, "invalid string %s: %v\n", args[1], err)
+				return
+			}
+		}
+		if s.validate != nil {
+			if err := s.validate(v); err != nil {
+				fmt.Fprintf(output, "invalid value %q for %s: %v\n", v, args[0], err)
+				return
+			}
+		}
+		s.set(v)
+		return
+	}
+	if len(args) != 2 {
+		fmt.Fprintf(output, "usage: set %s %s\n", args[0], strings.Join(s.allowed, "|"))
+		return
+	}
+	for _, v := range s.allowed {
+		if v == args[1] {
+			s.set(v)
+			if args[0] == "verbose" {
+				clearVerboseCategories()
+			}
+			return
+		}
+	}
+	fmt.Fprintf(output, "invalid value %q for %s; must be one of %s\n", args[1], args[0], strings.Join(s.allowed, ", "))
+}
+
+// verboseCategories are the kinds of notices that "set verbose <category>"
+// turns on and off independently of the verbose setting.
+var verboseCategories = map[string]string{
+	"break":     "the notices saying why the program paused, e.g. at a breakpoint on a return",
+	"defer":     "the <Running deferred function> marker",
+	"goroutine": "the notices when the debugger starts or stops following a goroutine",
+	"select":    "the notices around select statements",
+	"send":      "the values that channel sends on the line paused at are about to send",
+	"watch":     "the notices when a watched length changes",
+}
+
+// verboseOverrides holds the categories set with "set verbose <category>". A
+// category that is not in it follows the verbose setting. It is guarded by
+// settingsMu.
+var verboseOverrides = make(map[string]bool)
+
+// verbose reports whether notices of the given category are printed.
+func verbose(category string) bool {
+	settingsMu.RLock()
+	on, ok := verboseOverrides[category]
+	settingsMu.RUnlock()
+	if ok {
+		return on
+	}
+	return getSetting("verbose") == "on"
+}
+
+// setVerboseCategory implements "set verbose <category> on|off".
+func setVerboseCategory(args []string) {
+	if len(args) != 2 || (args[1] != "on" && args[1] != "off") {
+		fmt.Fprintln(output, "usage: set verbose <category> on|off")
+		return
+	}
+	if _, ok := verboseCategories[args[0]]; !ok {
+		fmt.Fprintf(output, "unknown verbose category %q; must be one of %s\n", args[0], strings.Join(sortedCategories(), ", "))
+		return
+	}
+	settingsMu.Lock()
+	verboseOverrides[args[0]] = args[1] == "on"
+	settingsMu.Unlock()
+}
+
+// clearVerboseCategories makes every category follow the verbose setting again.
+func clearVerboseCategories() {
+	settingsMu.Lock()
+	verboseOverrides = make(map[string]bool)
+	settingsMu.Unlock()
+}
+
+// printVerbose implements "info verbose".
+func printVerbose() {
+	for _, name := range sortedCategories() {
+		state := "off"
+		if verbose(name) {
+			state = "on"
+		}
+		fmt.Fprintf(output, "%-9s %-3s %s\n", name, state, verboseCategories[name])
+	}
+}
+
+func sortedCategories() []string {
+	names := make([]string, 0, len(verboseCategories))
+	for name := range verboseCategories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedSettings() []string {
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateCount accepts non-negative integers.
+func validateCount(v string) error {
+	n, err := strconv.Atoi(v)
+	switch {
+	case err != nil:
+		return errors.New("must be a number")
+	case n < 0:
+		return errors.New("must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package godebug
+
+// This file implements the "skip" command.
+
+import "fmt"
+
+// skipping holds the pause "skip" was entered at. Until the next pause, calls
+// that function makes to generated functions return at once. Like nextCount,
+// it is only touched by the goroutine the debugger follows.
+var skipping struct {
+	ctx     *Context
+	line    int
+	skipped int
+}
+
+// startSkip implements "skip".
+func startSkip(c *Context, line int) {
+	skipping.ctx, skipping.line, skipping.skipped = c, line, 0
+	currentState = next
+}
+
+// skipCall reports whether a generated function being entered by g, the
+// followed goroutine, should return without running. pc is in that function.
+func skipCall(g *goroutine, pc uintptr) bool {
+	if skipping.ctx == nil || g.top != skipping.ctx {
+		return false
+	}
+	skipping.skipped++
+	fmt.Fprintf(output, "< skipped %s() >\n", funcNameForPC(pc))
+	return true
+}
+
+// endSkip is called at every pause. It says if skip had nothing to skip.
+func endSkip() {
+	if skipping.ctx != nil && skipping.skipped == 0 {
+		fmt.Fprintf(output, "< nothing skipped: line %d calls no function godebug generated code for, so it ran as usual >\n", skipping.line)
+	}
+	skipping.ctx = nil
+}
@@ -0,0 +1,94 @@
+package godebug
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Snapshot describes the state of the program at a point where the debugger paused.
+type Snapshot struct {
+	// Goroutine is the id godebug assigned to the paused goroutine.
+	Goroutine uint32
+
+	// Line is the line number the debugger paused at, and Source is the
+	// text of that line with surrounding whitespace removed.
+	Line   int
+	Source string
+
+	// Locals holds the values of the variables in scope in the paused function,
+	// copied at the time of the pause.
+	Locals map[string]interface{}
+
+	// Scope is the paused function's scope, for evaluating expressions with
+	// Scope.Eval. Unlike Locals, it sees the variables as they are now.
+	Scope *Scope
+}
+
+func newSnapshot(s *Scope, line int) Snapshot {
+	return Snapshot{
+		Goroutine: atomic.LoadUint32(&currentGoroutine),
+		Line:      line,
+		Source:    s.sourceLine(line),
+		Locals:    copyLocals(s),
+		Scope:     s,
+	}
+}
+
+var (
+	eventsOnce sync.Once
+	events     chan Snapshot
+	eventsOn   int32
+)
+
+// Events returns a channel that receives a Snapshot every time the debugger
+// pauses, for observers such as live dashboards. Every call returns the same
+// channel. It is buffered, and a Snapshot that does not fit is dropped, so
+// a slow observer never holds up the program. Snapshots are only made once
+// Events has been called.
+//
+// Unlike a PauseHandler, an observer runs while the program does, so it must
+// not call Eval on the Snapshot's Scope.
+func Events() <-chan Snapshot {
+	eventsOnce.Do(func() {
+		events = make(chan Snapshot, 64)
+		atomic.StoreInt32(&eventsOn, 1)
+	})
+	return events
+}
+
+// sendEvent sends a Snapshot of a pause to Events, if there is room.
+func sendEvent(s *Scope, line int) {
+	if atomic.LoadInt32(&eventsOn) == 0 {
+		return
+	}
+	select {
+	case events <- newSnapshot(s, line):
+	default:
+	}
+}
+
+// A PauseHandler is called in place of the interactive prompt when the debugger pauses.
+// It returns the command to run, exactly as if it had been typed at the prompt. The
+// handler is called again after any command that does not resume the program, such
+// as print.
+type PauseHandler func(Snapshot) (command string)
+
+var pauseHandler PauseHandler
+
+// SetPauseHandler installs h to be consulted at every pause instead of the user.
+// While a handler is installed, godebug does not print the line it paused at;
+// the handler can find it in the Snapshot. Passing nil restores the prompt.
+//
+// SetPauseHandler is meant to be called before the program reaches a breakpoint.
+// It is not safe to call while the debugger is paused.
+func SetPauseHandler(h PauseHandler) {
+	pauseHandler = h
+}
+
+// nextCommand returns the next command to run at a pause.
+func nextCommand(scope *Scope, line int) (response string, ok bool) {
+	if h := pauseHandler; h != nil {
+		return h(newSnapshot(scope, line)), true
+	}
+	return promptUser()
+}
@@ -0,0 +1,41 @@
+package godebug
+
+// This file implements the step-filter setting, which makes step and next
+// pause only at lines whose source matches a regular expression.
+
+import (
+	"regexp"
+	"sync"
+)
+
+func validateStepFilter(v string) error {
+	if v == "off" {
+		return nil
+	}
+	_, err := regexp.Compile(v)
+	return err
+}
+
+// stepFilter caches the compiled step-filter, so that it is compiled once
+// rather than at every line stepped over.
+var stepFilter struct {
+	sync.Mutex
+	src string
+	re  *regexp.Regexp
+}
+
+// filteredOut reports whether step-filter keeps the debugger from pausing at
+// the line while stepping.
+func filteredOut(s *Scope, line int) bool {
+	src := getSetting("step-filter")
+	if src == "off" {
+		return false
+	}
+	stepFilter.Lock()
+	if stepFilter.re == nil || stepFilter.src != src {
+		stepFilter.src, stepFilter.re = src, regexp.MustCompile(src)
+	}
+	re := stepFilter.re
+	stepFilter.Unlock()
+	return !re.MatchString(s.sourceLine(line))
+}
@@ -0,0 +1,106 @@
+package godebug
+
+// This file implements the trace-policy setting, which says what happens when
+// a goroutine reaches a breakpoint while the debugger follows another one.
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// A pendingTrace is a goroutine that reached a breakpoint while the debugger
+// was following another goroutine.
+type pendingTrace struct {
+	id       uint32
+	funcName string
+}
+
+// pendingTraces holds the goroutines waiting to be followed under the queue
+// and prompt trace policies, oldest first. pendingTraceCount lets goroutines
+// that exit skip the lock when it is empty.
+var (
+	pendingTracesMu   sync.Mutex
+	pendingTraces     []pendingTrace
+	pendingTraceCount int32
+)
+
+// queueTrace records that c's goroutine reached a breakpoint while the
+// debugger was following another goroutine, unless trace-policy is first.
+func queueTrace(c *Context) {
+	if getSetting("trace-policy") == "first" {
+		return
+	}
+	pendingTracesMu.Lock()
+	defer pendingTracesMu.Unlock()
+	for _, t := range pendingTraces {
+		if t.id == c.goroutine {
+			return
+		}
+	}
+	pendingTraces = append(pendingTraces, pendingTrace{id: c.goroutine, funcName: c.funcName()})
+	atomic.StoreInt32(&pendingTraceCount, int32(len(pendingTraces)))
+}
+
+// dropTrace forgets goroutine id, which has exited.
+func dropTrace(id uint32) {
+	pendingTracesMu.Lock()
+	defer pendingTracesMu.Unlock()
+	for i, t := range pendingTraces {
+		if t.id == id {
+			pendingTraces = append(pendingTraces[:i], pendingTraces[i+1:]...)
+			atomic.StoreInt32(&pendingTraceCount, int32(len(pendingTraces)))
+			return
+		}
+	}
+}
+
+// takeTrace is called by "continue". Under the queue policy, it makes the
+// debugger follow the goroutine that has waited longest, which pauses at the
+// next line it runs. Under the prompt policy, it asks which waiting goroutine
+// to follow, and forgets the rest.
+func takeTrace(p prompt) {
+	if atomic.LoadInt32(&pendingTraceCount) == 0 {
+		return
+	}
+	pendingTracesMu.Lock()
+	waiting := pendingTraces
+	pendingTraces = nil
+	if getSetting("trace-policy") == "queue" {
+		pendingTraces = waiting[1:]
+		waiting = waiting[:1]
+	}
+	atomic.StoreInt32(&pendingTraceCount, int32(len(pendingTraces)))
+	pendingTracesMu.Unlock()
+
+	t := waiting[0]
+	if len(waiting) > 1 || getSetting("trace-policy") == "prompt" {
+		var ok bool
+		if t, ok = choosePendingTrace(p, waiting); !ok {
+			return
+		}
+	}
+	from := atomic.LoadUint32(&currentGoroutine)
+	atomic.StoreUint32(&currentGoroutine, t.id)
+	lastPause.ctx = nil
+	currentState = step
+	notify("goroutine", fmt.Sprintf("< following goroutine %s, which reached a breakpoint in %s while goroutine %s was followed >", goroutineName(t.id), t.funcName, goroutineName(from)))
+}
+
+// choosePendingTrace asks which of the waiting goroutines to follow.
+func choosePendingTrace(p prompt, waiting []pendingTrace) (t pendingTrace, ok bool) {
+	fmt.Fprintf(output, "These goroutines reached a breakpoint while goroutine %s was followed:\n", goroutineName(atomic.LoadUint32(&currentGoroutine)))
+	for i, t := range waiting {
+		fmt.Fprintf(output, "  %d: goroutine %s in %s\n", i+1, goroutineName(t.id), t.funcName)
+	}
+	fmt.Fprintln(output, "Follow which? Enter its number, or nothing to follow none of them.")
+	answer, _ := nextCommand(p.scope, p.line)
+	n, err := strconv.Atoi(strings.TrimSpace(answer))
+	if err != nil || n < 1 || n > len(waiting) {
+		fmt.Fprintln(output, "Following none of them.")
+		return pendingTrace{}, false
+	}
+	return waiting[n-1], true
+}
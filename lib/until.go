@@ -0,0 +1,50 @@
+package godebug
+
+// This file implements the "until" command.
+
+import (
+	"fmt"
+	"strings"
+)
+
+// until is the frame "until" was entered in and the line it runs to. Like
+// finishing, it is only touched by the goroutine the debugger follows, and it
+// is cleared at the next pause.
+var until struct {
+	ctx  *Context
+	line int
+}
+
+const untilUsage = "usage: until <line>"
+
+// untilCommand implements "until <line>". The program runs without pausing
+// until the current frame reaches the line or returns. Lines reached in other
+// frames, such as deeper calls to the same function, do not count.
+func untilCommand(c *Context, s *Scope, arg string) bool {
+	if arg = strings.TrimSpace(arg); arg == "" || strings.Contains(arg, ":") {
+		fmt.Fprintln(output, untilUsage)
+		return false
+	}
+	key, ok := parseFileLine(s.file, arg, untilUsage)
+	if !ok {
+		return false
+	}
+	until.ctx, until.line = c, key.line
+	currentState = next
+	debuggerDepth = currentDepth - 1
+	return true
+}
+
+// reachedUntil reports whether the line c is at is the one "until" runs to.
+func reachedUntil(c *Context, line int) bool {
+	return until.ctx == c && until.line == line
+}
+
+// leftUntil is called by ExitFunc for the frame "until" was entered in, which
+// returned without reaching the line. The program pauses in the caller, as it
+// does after finish.
+func leftUntil(c *Context) {
+	fmt.Fprintf(output, "< %s() returned before reaching line %d >\n", c.funcName(), until.line)
+	until.ctx = nil
+	justLeft = true
+}
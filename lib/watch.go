@@ -0,0 +1,374 @@
+package godebug
+
+// This file implements the "watch" and "break when" commands.
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// A watch pauses the program when the value of a variable changes, when its
+// length changes, or, if it has a limit, when the length first grows past the
+// limit.
+type watch struct {
+	name string
+	v    reflect.Value // the variable itself, addressed through its pointer
+
+	// frame is the call the variable is a local of, or nil for a package
+	// variable. The watch is dropped when that call returns.
+	frame *Context
+
+	// whole is set by "watch <variable>", which compares a copy of the value,
+	// lastValue, with the variable. The other watches compare lengths, and
+	// keep the length last seen in last.
+	whole bool
+
+	// limit is set by "break when len <variable> > <limit>"; it is -1 for
+	// "watch len". once removes the watch after it first fires.
+	limit int
+	once  bool
+
+	// mu guards the rest, which change as the watch is checked.
+	mu        sync.Mutex
+	lastValue reflect.Value
+	last      int
+	fired     int  // how many times the watch has paused the program
+	spent     bool // a once watch has fired, and is about to be removed
+}
+
+// String describes w the way debug dump lists it.
+func (w *watch) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.whole {
+		return fmt.Sprintf("%s, last %s", w.name, valueString(w.name, w.lastValue))
+	}
+	s := fmt.Sprintf("len(%s), last %d", w.name, w.last)
+	if w.limit >= 0 {
+		s = fmt.Sprintf("len(%s) > %d, last %d", w.name, w.limit, w.last)
+	}
+	if w.once {
+		s += ", once"
+	}
+	return s
+}
+
+// command returns the command that sets w.
+func (w *watch) command() string {
+	if w.whole {
+		return "watch " + w.name
+	}
+	cmd := fmt.Sprintf("watch len %s", w.name)
+	if w.limit >= 0 {
+		cmd = fmt.Sprintf("break when len %s > %d", w.name, w.limit)
+	}
+	if w.once {
+		cmd += " once"
+	}
+	return cmd
+}
+
+// watches holds the watches, in the order they were set. Every line of
+// generated code, and every assignment, consults it, so it is a []*watch that
+// is replaced rather than changed, and read without a lock. watchesMu
+// serializes the replacements, and watchCount lets the readers skip the
+// checks when there are no watches.
+var (
+	watches    atomic.Value
+	watchesMu  sync.Mutex
+	watchCount int32
+)
+
+func loadWatches() []*watch {
+	ws, _ := watches.Load().([]*watch)
+	return ws
+}
+
+// watchCommand implements "watch [len] <variable>". frame is the call scope
+// belongs to, whose return ends watches on its locals.
+func watchCommand(frame *Context, scope *Scope, args []string) {
+	if len(args) == 1 && args[0] != "len" {
+		watchValueCommand(frame, scope, args[0])
+		return
+	}
+	if len(args) != 2 || args[0] != "len" {
+		fmt.Fprintln(output, "usage: watch [len] <variable>")
+		return
+	}
+	w, ok := newLenWatch(frame, scope, args[1], "watch len")
+	if !ok {
+		return
+	}
+	w.limit = -1
+	addWatch(w)
+	fmt.Fprintf(output, "Watching len(%s), currently %d.\n", w.name, w.last)
+}
+
+// watchValueCommand implements "watch <variable>".
+func watchValueCommand(frame *Context, scope *Scope, name string) {
+	ptr, local, ok := watchedVar(scope, name)
+	if !ok {
+		fmt.Fprintf(output, "%s is not a variable in scope\n", name)
+		return
+	}
+	v := reflect.ValueOf(ptr).Elem()
+	w := &watch{name: name, v: v, whole: true, lastValue: snapshotValue(v), limit: -1}
+	if local {
+		w.frame = frame
+	}
+	addWatch(w)
+	fmt.Fprintf(output, "Watching %s, currently %s.\n", name, valueString(name, w.lastValue))
+}
+
+// snapshotValue returns a copy of v that later changes to v do not show in.
+// Like the copies of locals kept for diff, it is shallow below maxCopyDepth,
+// and pointers are copied as addresses, so a change to what a pointer
+// points to is not a change to the pointer.
+func snapshotValue(v reflect.Value) reflect.Value {
+	c := reflect.New(v.Type()).Elem()
+	c.Set(copyValue(v, maxCopyDepth, newCopyBudget()))
+	return c
+}
+
+const breakWhenUsage = "usage: break when len <variable> > <n> [once]"
+
+// breakWhenCommand implements "break when len <variable> > <n> [once]".
+func breakWhenCommand(frame *Context, scope *Scope, args []string) {
+	if len(args) != 4 && (len(args) != 5 || args[4] != "once") || args[0] != "len" || args[2] != ">" {
+		fmt.Fprintln(output, breakWhenUsage)
+		return
+	}
+	limit, err := strconv.Atoi(args[3])
+	if err != nil || limit < 0 {
+		fmt.Fprintln(output, breakWhenUsage)
+		return
+	}
+	w, ok := newLenWatch(frame, scope, args[1], "break when len")
+	if !ok {
+		return
+	}
+	w.limit, w.once = limit, len(args) == 5
+	addWatch(w)
+	fmt.Fprintf(output, "Will pause when len(%s) grows past %d; it is %d now.\n", w.name, limit, w.last)
+}
+
+// newLenWatch returns a watch on the length of the named variable, or reports
+// why there can be none. cmd names the command for the error message.
+func newLenWatch(frame *Context, scope *Scope, name, cmd string) (*watch, bool) {
+	ptr, local, ok := watchedVar(scope, name)
+	if !ok {
+		fmt.Fprintf(output, "%s is not a variable in scope\n", name)
+		return nil, false
+	}
+	v := reflect.ValueOf(ptr).Elem()
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Chan:
+	default:
+		fmt.Fprintf(output, "%s is a %s; %s only works on slices, maps and channels\n", name, v.Type(), cmd)
+		return nil, false
+	}
+	w := &watch{name: name, v: v, last: v.Len()}
+	if local {
+		w.frame = frame
+	}
+	return w, true
+}
+
+// watchedVar returns a pointer to the named variable, and whether it is a
+// local of the call scope belongs to rather than a package variable.
+func watchedVar(scope *Scope, name string) (ptr interface{}, local, ok bool) {
+	if ptr, ok = scope.getVar(name); !ok {
+		return nil, false, false
+	}
+	for s := scope; s != nil && !s.isFile; s = s.parent {
+		if p, found := s.Vars[name]; found && p == ptr {
+			return ptr, true, true
+		}
+	}
+	return ptr, false, true
+}
+
+func addWatch(w *watch) {
+	watchesMu.Lock()
+	defer watchesMu.Unlock()
+	old := loadWatches()
+	ws := make([]*watch, len(old), len(old)+1)
+	copy(ws, old)
+	ws = append(ws, w)
+	watches.Store(ws)
+	atomic.StoreInt32(&watchCount, int32(len(ws)))
+}
+
+// removeWatches removes the watches drop reports true for, and returns them.
+func removeWatches(drop func(*watch) bool) (removed []*watch) {
+	watchesMu.Lock()
+	defer watchesMu.Unlock()
+	old := loadWatches()
+	ws := make([]*watch, 0, len(old))
+	for _, w := range old {
+		if drop(w) {
+			removed = append(removed, w)
+		} else {
+			ws = append(ws, w)
+		}
+	}
+	if len(removed) > 0 {
+		watches.Store(ws)
+		atomic.StoreInt32(&watchCount, int32(len(ws)))
+	}
+	return removed
+}
+
+// checkWatches reports whether a watched value or length has changed since
+// it was last checked. If one has, the debugger follows c's goroutine from
+// here on, just as it would after a "breakpoint" statement. It is called
+// after every assignment in generated code, so a change made there pauses at
+// the line that made it, and at every line, which catches the changes made
+// elsewhere, e.g. by a send or by code that is not generated, at the line
+// after.
+//
+// Watches are only checked by the goroutine the debugger is following, or by
+// any goroutine while the program is running freely.
+func checkWatches(c *Context) bool {
+	if atomic.LoadInt32(&currentState) != run && atomic.LoadUint32(&currentGoroutine) != c.goroutine {
+		return false
+	}
+	var fired *watch
+	spent := false
+	for _, w := range loadWatches() {
+		if w.check() {
+			if fired == nil {
+				fired = w
+			}
+			spent = spent || w.once
+		}
+	}
+	if spent {
+		removeWatches(func(w *watch) bool {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			return w.spent
+		})
+	}
+	if fired != nil && atomic.LoadInt32(&currentState) == run {
+		atomic.StoreUint32(&currentGoroutine, c.goroutine)
+		currentState = step
+		pauseReason = fired.command()
+	}
+	return fired != nil
+}
+
+// check reports whether w fires, and says why.
+func (w *watch) check() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.spent {
+		return false
+	}
+	// TODO: This can race with other goroutines changing the variable.
+	if w.whole {
+		if _, _, why := firstDifference(w.lastValue, w.v, w.name, w.name, maxCopyDepth); why == "" {
+			return false
+		}
+		notify("watch", fmt.Sprintf("< %s changed from %s to %s >", w.name, valueString(w.name, w.lastValue), valueString(w.name, w.v)))
+		w.fired++
+		w.lastValue = snapshotValue(w.v)
+		return true
+	}
+	n := w.v.Len()
+	fired := false
+	switch {
+	case n == w.last:
+	case w.limit < 0:
+		notify("watch", fmt.Sprintf("< len(%s) changed from %d to %d >", w.name, w.last, n))
+		fired = true
+	case w.last <= w.limit && n > w.limit:
+		notify("watch", fmt.Sprintf("< len(%s) is %d, past %d >", w.name, n, w.limit))
+		fired = true
+		w.spent = w.once
+	}
+	if fired {
+		w.fired++
+	}
+	w.last = n
+	return fired
+}
+
+// Assigned is called after an assignment that may change a watched variable.
+// If it does, the program pauses at the assignment.
+func Assigned(c *Context, s *Scope, line int) {
+	if atomic.LoadInt32(&watchCount) == 0 || isDetached() {
+		return
+	}
+	c.scope, c.line = s, line
+	if checkWatches(c) {
+		pause(c, s, line, "")
+	}
+}
+
+// dropFrameWatches removes the watches on the locals of the call that is
+// returning, whose variables the program no longer uses.
+func dropFrameWatches(ctx *Context) {
+	found := false
+	for _, w := range loadWatches() {
+		found = found || w.frame == ctx
+	}
+	if !found {
+		return
+	}
+	for _, w := range removeWatches(func(w *watch) bool { return w.frame == ctx }) {
+		notify("watch", fmt.Sprintf("< %s returned; no longer watching %s >", ctx.funcName(), w.on()))
+	}
+}
+
+// on names what w watches: the variable, or its length.
+func (w *watch) on() string {
+	if w.whole {
+		return w.name
+	}
+	return "len(" + w.name + ")"
+}
+
+// printWatches implements "info watch". The watches are numbered from 1, in
+// the order they were set, for "delete watch".
+func printWatches() {
+	ws := loadWatches()
+	if len(ws) == 0 {
+		fmt.Fprintln(output, "No watches.")
+		return
+	}
+	for i, w := range ws {
+		w.mu.Lock()
+		times := "times"
+		if w.fired == 1 {
+			times = "time"
+		}
+		last := fmt.Sprintf("length last %d", w.last)
+		if w.whole {
+			last = "value last " + valueString(w.name, w.lastValue)
+		}
+		fmt.Fprintf(output, "%d: %s; %s, fired %d %s\n", i+1, w.command(), last, w.fired, times)
+		w.mu.Unlock()
+	}
+}
+
+// deleteWatchCommand implements "delete watch <n>".
+func deleteWatchCommand(arg string) {
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		fmt.Fprintln(output, "usage: delete watch <n>")
+		return
+	}
+	var target *watch
+	if ws := loadWatches(); n >= 1 && n <= len(ws) {
+		target = ws[n-1]
+	}
+	if target == nil || len(removeWatches(func(w *watch) bool { return w == target })) == 0 {
+		fmt.Fprintf(output, "There is no watch %d; info watch lists them.\n", n)
+		return
+	}
+	fmt.Fprintf(output, "Deleted watch %d, on %s.\n", n, target.on())
+}
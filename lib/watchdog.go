@@ -0,0 +1,106 @@
+package godebug
+
+// This file implements the watchdog setting, which reports when the goroutine
+// the debugger follows seems to be blocked.
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// watchdogGen changes at every pause, which ends the watchdog started when
+// the program last resumed. watchdogLive is 1 while a watchdog runs, so that
+// goroutines record when they run lines only when someone is looking.
+var (
+	watchdogGen  uint32
+	watchdogLive int32
+)
+
+func validateWatchdog(v string) error {
+	if v == "off" {
+		return nil
+	}
+	if d, err := time.ParseDuration(v); err != nil || d <= 0 {
+		return errors.New(`must be "off" or a duration, like 5s`)
+	}
+	return nil
+}
+
+// stopWatchdog ends the watchdog, if one is running.
+func stopWatchdog() {
+	atomic.AddUint32(&watchdogGen, 1)
+	atomic.StoreInt32(&watchdogLive, 0)
+}
+
+// startWatchdog is called as the program resumes. If the watchdog setting is
+// on and the debugger is stepping, it checks every interval that the followed
+// goroutine has run a line. If it has not, it says so, and if other goroutines
+// have, the next of them to run a line is paused, as with "continue switch".
+func startWatchdog() {
+	d, err := time.ParseDuration(getSetting("watchdog"))
+	if err != nil || currentState == run {
+		return
+	}
+	gen := atomic.AddUint32(&watchdogGen, 1)
+	atomic.StoreInt32(&watchdogLive, 1)
+	followed := atomic.LoadUint32(&currentGoroutine)
+	resumed := time.Now().UnixNano()
+	go func() {
+		for {
+			time.Sleep(d)
+			if atomic.LoadUint32(&watchdogGen) != gen {
+				return
+			}
+			since := time.Now().Add(-d).UnixNano()
+			if lastActive(followed) > since {
+				continue
+			}
+			others := activeSince(resumed, followed)
+			if len(others) == 0 {
+				notify("goroutine", fmt.Sprintf("< followed goroutine %s appears blocked, and no other goroutine has run generated code since it resumed >", goroutineName(followed)))
+				return
+			}
+			atomic.StoreUint32(&switchFrom, followed)
+			atomic.StoreInt32(&switchArmed, 1)
+			notify("goroutine", fmt.Sprintf("< followed goroutine %s appears blocked; other goroutines are running: %s. Pausing the next of them to run a line >", goroutineName(followed), strings.Join(others, ", ")))
+			return
+		}
+	}()
+}
+
+// markActive records that c's goroutine is running a line, for the watchdog.
+func markActive(c *Context) {
+	atomic.StoreInt64(&c.g.lastActive, time.Now().UnixNano())
+}
+
+func lastActive(id uint32) int64 {
+	goroutines.Lock()
+	defer goroutines.Unlock()
+	if g := goroutines.m[id]; g != nil {
+		return atomic.LoadInt64(&g.lastActive)
+	}
+	return 0
+}
+
+// activeSince returns the names of the goroutines other than skip that have
+// run a line since the given time, in order of id.
+func activeSince(t int64, skip uint32) []string {
+	var ids []int
+	goroutines.Lock()
+	for id, g := range goroutines.m {
+		if id != skip && atomic.LoadInt64(&g.lastActive) > t {
+			ids = append(ids, int(id))
+		}
+	}
+	goroutines.Unlock()
+	sort.Ints(ids)
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = goroutineName(uint32(id))
+	}
+	return names
+}
@@ -0,0 +1,18 @@
+package main
+
+type point struct {
+	x, y int
+}
+
+func main() {
+	n := 1
+	var f float64
+	var g float32
+	var u uint8
+	var p *point
+	pt := point{1, 2}
+	s := "before"
+	var verbose bool
+	_ = "breakpoint"
+	println(n, f, g, u, p == nil, pt.x, pt.y, s, verbose)
+}
@@ -0,0 +1,79 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var assign_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/assign-in.go", assign_in_go_contents)
+
+type point struct {
+	x, y int
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, assign_in_go_scope, 8)
+	n := 1
+	scope := assign_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 9)
+	var f float64
+	scope.Declare("f", &f)
+	godebug.Line(ctx, scope, 10)
+	var g float32
+	scope.Declare("g", &g)
+	godebug.Line(ctx, scope, 11)
+	var u uint8
+	scope.Declare("u", &u)
+	godebug.Line(ctx, scope, 12)
+	var p *point
+	scope.Declare("p", &p)
+	godebug.Line(ctx, scope, 13)
+	pt := point{1, 2}
+	scope.Declare("pt", &pt)
+	godebug.Line(ctx, scope, 14)
+	s := "before"
+	scope.Declare("s", &s)
+	godebug.Line(ctx, scope, 15)
+	var verbose bool
+	scope.Declare("verbose", &verbose)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 16)
+	godebug.Line(ctx, scope, 17)
+
+	println(n, f, g, u, p == nil, pt.x, pt.y, s, verbose)
+}
+
+var assign_in_go_contents = `package main
+
+type point struct {
+	x, y int
+}
+
+func main() {
+	n := 1
+	var f float64
+	var g float32
+	var u uint8
+	var p *point
+	pt := point{1, 2}
+	s := "before"
+	var verbose bool
+	_ = "breakpoint"
+	println(n, f, g, u, p == nil, pt.x, pt.y, s, verbose)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,16 @@
+package main
+
+func fact(n int) int {
+	if n <= 1 {
+		_ = "breakpoint"
+		return 1
+	}
+	return n * fact(n-1)
+}
+
+func main() {
+	apply := func(f func(int) int) int {
+		return f(3)
+	}
+	println(apply(fact))
+}
@@ -0,0 +1,88 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var backtrace_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/backtrace-in.go", backtrace_in_go_contents)
+
+func fact(n int) (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = fact(n)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := backtrace_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 4)
+	if n <= 1 {
+		godebug.SetTraceGen(ctx)
+		godebug.Line(ctx, scope, 5)
+		godebug.Line(ctx, scope, 6)
+
+		return 1
+	}
+	godebug.Line(ctx, scope, 8)
+	return n * fact(n-1)
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, backtrace_in_go_scope, 12)
+	apply := func(f func(int) int) int {
+		var result1 int
+		fn := func(ctx *godebug.Context) {
+			result1 = func() int {
+				scope := backtrace_in_go_scope.EnteringNewChildScope()
+				scope.Declare("f", &f)
+				godebug.Line(ctx, scope, 13)
+				return f(3)
+			}()
+		}
+		if ctx, ok := godebug.EnterFuncLit(fn); ok {
+			defer godebug.ExitFunc(ctx, &result1)
+			fn(ctx)
+		}
+		return result1
+	}
+	scope := backtrace_in_go_scope.EnteringNewChildScope()
+	scope.Declare("apply", &apply)
+	godebug.Line(ctx, scope, 15)
+
+	println(apply(fact))
+}
+
+var backtrace_in_go_contents = `package main
+
+func fact(n int) int {
+	if n <= 1 {
+		_ = "breakpoint"
+		return 1
+	}
+	return n * fact(n-1)
+}
+
+func main() {
+	apply := func(f func(int) int) int {
+		return f(3)
+	}
+	println(apply(fact))
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"fact": fact,
+		"main": main,
+	}
+}
\ No newline at end of file
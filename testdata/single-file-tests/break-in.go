@@ -0,0 +1,16 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+func check(n int) {
+	if n == 3 {
+		godebug.Break()
+	}
+}
+
+func main() {
+	for i := 0; i < 5; i++ {
+		check(i)
+	}
+	println("done")
+}
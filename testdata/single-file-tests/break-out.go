@@ -0,0 +1,73 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var break_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/break-in.go", break_in_go_contents)
+
+func check(n int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		check(n)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := break_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 6)
+	if n == 3 {
+		godebug.Line(ctx, scope, 7)
+		godebug.Break()
+	}
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	{
+		scope := break_in_go_scope.EnteringNewChildScope()
+		for i := 0; i < 5; i++ {
+			godebug.Line(ctx, scope, 12)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 13)
+			check(i)
+		}
+		godebug.Line(ctx, scope, 12)
+	}
+	godebug.Line(ctx, break_in_go_scope, 15)
+	println("done")
+}
+
+var break_in_go_contents = `package main
+
+import "github.com/mailgun/godebug/lib"
+
+func check(n int) {
+	if n == 3 {
+		godebug.Break()
+	}
+}
+
+func main() {
+	for i := 0; i < 5; i++ {
+		check(i)
+	}
+	println("done")
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"check": check,
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,17 @@
+package main
+
+func main() {
+	_ = "breakpoint"
+	for i := 0; i < 3; i++ {
+		first(i)
+		second(i)
+	}
+}
+
+func first(i int) {
+	println("first", i)
+}
+
+func second(i int) {
+	println("second", i)
+}
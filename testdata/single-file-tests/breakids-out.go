@@ -0,0 +1,89 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var breakids_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "breakids-in.go", breakids_in_go_contents)
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, breakids_in_go_scope, 4)
+	{
+		scope := breakids_in_go_scope.EnteringNewChildScope()
+
+		for i := 0; i < 3; i++ {
+			godebug.Line(ctx, scope, 5)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 6)
+			first(i)
+			godebug.Line(ctx, scope, 7)
+			second(i)
+		}
+		godebug.Line(ctx, scope, 5)
+	}
+}
+
+func first(i int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		first(i)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := breakids_in_go_scope.EnteringNewChildScope()
+	scope.Declare("i", &i)
+	godebug.Line(ctx, scope, 12)
+	println("first", i)
+}
+
+func second(i int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		second(i)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := breakids_in_go_scope.EnteringNewChildScope()
+	scope.Declare("i", &i)
+	godebug.Line(ctx, scope, 16)
+	println("second", i)
+}
+
+var breakids_in_go_contents = `package main
+
+func main() {
+	_ = "breakpoint"
+	for i := 0; i < 3; i++ {
+		first(i)
+		second(i)
+	}
+}
+
+func first(i int) {
+	println("first", i)
+}
+
+func second(i int) {
+	println("second", i)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+		"first": first,
+		"second": second,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,14 @@
+package main
+
+type Buf []byte
+
+func main() {
+	text := []byte("hello, world\n")
+	bin := []byte{0xff, 0x00, 0x01}
+	named := Buf("abc")
+	var empty []byte
+	s := "hi"
+	n := 255
+	_ = "breakpoint"
+	_, _, _, _, _, _ = text, bin, named, empty, s, n
+}
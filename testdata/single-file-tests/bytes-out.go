@@ -0,0 +1,67 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var bytes_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/bytes-in.go", bytes_in_go_contents)
+
+type Buf []byte
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, bytes_in_go_scope, 6)
+	text := []byte("hello, world\n")
+	scope := bytes_in_go_scope.EnteringNewChildScope()
+	scope.Declare("text", &text)
+	godebug.Line(ctx, scope, 7)
+	bin := []byte{0xff, 0x00, 0x01}
+	scope.Declare("bin", &bin)
+	godebug.Line(ctx, scope, 8)
+	named := Buf("abc")
+	scope.Declare("named", &named)
+	godebug.Line(ctx, scope, 9)
+	var empty []byte
+	scope.Declare("empty", &empty)
+	godebug.Line(ctx, scope, 10)
+	s := "hi"
+	scope.Declare("s", &s)
+	godebug.Line(ctx, scope, 11)
+	n := 255
+	scope.Declare("n", &n)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 12)
+	godebug.Line(ctx, scope, 13)
+
+	_, _, _, _, _, _ = text, bin, named, empty, s, n
+}
+
+var bytes_in_go_contents = `package main
+
+type Buf []byte
+
+func main() {
+	text := []byte("hello, world\n")
+	bin := []byte{0xff, 0x00, 0x01}
+	named := Buf("abc")
+	var empty []byte
+	s := "hi"
+	n := 255
+	_ = "breakpoint"
+	_, _, _, _, _, _ = text, bin, named, empty, s, n
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
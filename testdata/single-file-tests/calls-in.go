@@ -0,0 +1,22 @@
+package main
+
+func fib(n int) int {
+	if n < 2 {
+		return n
+	}
+	return fib(n-1) + fib(n-2)
+}
+
+func square(n int) int {
+	return n * n
+}
+
+func main() {
+	x := fib(6)
+	_ = "breakpoint"
+	for i := 0; i < 3; i++ {
+		x += square(i)
+	}
+	_ = "breakpoint"
+	println(x)
+}
@@ -0,0 +1,107 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var calls_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/calls-in.go", calls_in_go_contents)
+
+func fib(n int) (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = fib(n)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := calls_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 4)
+	if n < 2 {
+		godebug.Line(ctx, scope, 5)
+		return n
+	}
+	godebug.Line(ctx, scope, 7)
+	return fib(n-1) + fib(n-2)
+}
+
+func square(n int) (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = square(n)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := calls_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 11)
+	return n * n
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, calls_in_go_scope, 15)
+	x := fib(6)
+	scope := calls_in_go_scope.EnteringNewChildScope()
+	scope.Declare("x", &x)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 16)
+	{
+		scope := scope.EnteringNewChildScope()
+
+		for i := 0; i < 3; i++ {
+			godebug.Line(ctx, scope, 17)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 18)
+			x += square(i)
+			godebug.Assigned(ctx, scope, 18)
+		}
+		godebug.Line(ctx, scope, 17)
+	}
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 20)
+	godebug.Line(ctx, scope, 21)
+
+	println(x)
+}
+
+var calls_in_go_contents = `package main
+
+func fib(n int) int {
+	if n < 2 {
+		return n
+	}
+	return fib(n-1) + fib(n-2)
+}
+
+func square(n int) int {
+	return n * n
+}
+
+func main() {
+	x := fib(6)
+	_ = "breakpoint"
+	for i := 0; i < 3; i++ {
+		x += square(i)
+	}
+	_ = "breakpoint"
+	println(x)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"fib": fib,
+		"square": square,
+		"main": main,
+	}
+}
\ No newline at end of file
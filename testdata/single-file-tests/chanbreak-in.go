@@ -0,0 +1,25 @@
+package main
+
+type pool struct {
+	jobs, results chan int
+}
+
+func work(p *pool) {
+	for j := range p.jobs {
+		p.results <- j * 2
+	}
+}
+
+func main() {
+	p := &pool{jobs: make(chan int, 2), results: make(chan int, 2)}
+	other := make(chan int, 1)
+	var none chan int
+	n := 0
+	_ = "breakpoint"
+	other <- 1
+	n += <-other
+	p.jobs <- 3
+	close(p.jobs)
+	work(p)
+	println(<-p.results, n, none == nil)
+}
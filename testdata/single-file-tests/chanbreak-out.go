@@ -0,0 +1,108 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var chanbreak_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "chanbreak-in.go", chanbreak_in_go_contents)
+
+type pool struct {
+	jobs, results chan int
+}
+
+func work(p *pool) {
+	ctx, ok := godebug.EnterFunc(func() {
+		work(p)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := chanbreak_in_go_scope.EnteringNewChildScope()
+	scope.Declare("p", &p)
+	{
+		scope := scope.EnteringNewChildScope()
+		for j := range p.jobs {
+			godebug.Line(ctx, scope, 8)
+			scope.Declare("j", &j)
+			godebug.Line(ctx, scope, 9)
+			p.results <- j * 2
+		}
+		godebug.Line(ctx, scope, 8)
+	}
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, chanbreak_in_go_scope, 14)
+	p := &pool{jobs: make(chan int, 2), results: make(chan int, 2)}
+	scope := chanbreak_in_go_scope.EnteringNewChildScope()
+	scope.Declare("p", &p)
+	godebug.Line(ctx, scope, 15)
+	other := make(chan int, 1)
+	scope.Declare("other", &other)
+	godebug.Line(ctx, scope, 16)
+	var none chan int
+	scope.Declare("none", &none)
+	godebug.Line(ctx, scope, 17)
+	n := 0
+	scope.Declare("n", &n)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 18)
+	godebug.Line(ctx, scope, 19)
+
+	other <- 1
+	godebug.Line(ctx, scope, 20)
+	n += <-other
+	godebug.Assigned(ctx, scope, 20)
+	godebug.Line(ctx, scope, 21)
+	p.jobs <- 3
+	godebug.Line(ctx, scope, 22)
+	close(p.jobs)
+	godebug.Line(ctx, scope, 23)
+	work(p)
+	godebug.Line(ctx, scope, 24)
+	println(<-p.results, n, none == nil)
+}
+
+var chanbreak_in_go_contents = `package main
+
+type pool struct {
+	jobs, results chan int
+}
+
+func work(p *pool) {
+	for j := range p.jobs {
+		p.results <- j * 2
+	}
+}
+
+func main() {
+	p := &pool{jobs: make(chan int, 2), results: make(chan int, 2)}
+	other := make(chan int, 1)
+	var none chan int
+	n := 0
+	_ = "breakpoint"
+	other <- 1
+	n += <-other
+	p.jobs <- 3
+	close(p.jobs)
+	work(p)
+	println(<-p.results, n, none == nil)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"work": work,
+		"main": main,
+	}
+}
\ No newline at end of file
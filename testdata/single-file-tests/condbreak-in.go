@@ -0,0 +1,14 @@
+package main
+
+func main() {
+	count := 0
+	_ = "breakpoint"
+	for i := 0; i < 5; i++ {
+		count += i
+		report(count)
+	}
+}
+
+func report(count int) {
+	println("count", count)
+}
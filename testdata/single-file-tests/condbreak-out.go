@@ -0,0 +1,76 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var condbreak_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "condbreak-in.go", condbreak_in_go_contents)
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, condbreak_in_go_scope, 4)
+	count := 0
+	scope := condbreak_in_go_scope.EnteringNewChildScope()
+	scope.Declare("count", &count)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 5)
+	{
+		scope := scope.EnteringNewChildScope()
+
+		for i := 0; i < 5; i++ {
+			godebug.Line(ctx, scope, 6)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 7)
+			count += i
+			godebug.Assigned(ctx, scope, 7)
+			godebug.Line(ctx, scope, 8)
+			report(count)
+		}
+		godebug.Line(ctx, scope, 6)
+	}
+}
+
+func report(count int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		report(count)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := condbreak_in_go_scope.EnteringNewChildScope()
+	scope.Declare("count", &count)
+	godebug.Line(ctx, scope, 13)
+	println("count", count)
+}
+
+var condbreak_in_go_contents = `package main
+
+func main() {
+	count := 0
+	_ = "breakpoint"
+	for i := 0; i < 5; i++ {
+		count += i
+		report(count)
+	}
+}
+
+func report(count int) {
+	println("count", count)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+		"report": report,
+	}
+}
\ No newline at end of file
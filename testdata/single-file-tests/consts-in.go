@@ -0,0 +1,17 @@
+package main
+
+const (
+	maxRetries = 3
+	greeting   = "hello"
+	limit      = 100
+)
+
+type color int
+
+func main() {
+	const limit = 10
+	const red color = 2
+	maxRetries := 5
+	_ = "breakpoint"
+	println(maxRetries, greeting, limit, red)
+}
@@ -0,0 +1,70 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var consts_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/consts-in.go", consts_in_go_contents)
+
+const (
+	maxRetries = 3
+	greeting   = "hello"
+	limit      = 100
+)
+
+type color int
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, consts_in_go_scope, 12)
+	const limit = 10
+	scope := consts_in_go_scope.EnteringNewChildScope()
+	scope.Constant("limit", limit)
+	godebug.Line(ctx, scope, 13)
+	const red color = 2
+	scope.Constant("red", red)
+	godebug.Line(ctx, scope, 14)
+	maxRetries := 5
+	scope.Declare("maxRetries", &maxRetries)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 15)
+	godebug.Line(ctx, scope, 16)
+
+	println(maxRetries, greeting, limit, red)
+}
+
+var consts_in_go_contents = `package main
+
+const (
+	maxRetries = 3
+	greeting   = "hello"
+	limit      = 100
+)
+
+type color int
+
+func main() {
+	const limit = 10
+	const red color = 2
+	maxRetries := 5
+	_ = "breakpoint"
+	println(maxRetries, greeting, limit, red)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+		"maxRetries": maxRetries,
+		"greeting": greeting,
+		"limit": limit,
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
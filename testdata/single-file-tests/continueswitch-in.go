@@ -0,0 +1,16 @@
+package main
+
+func worker(handoff chan int) {
+	for i := 0; i < 2; i++ {
+		handoff <- i
+	}
+}
+
+func main() {
+	handoff := make(chan int)
+	_ = "breakpoint"
+	go worker(handoff)
+	a := <-handoff
+	b := <-handoff
+	println(a, b)
+}
@@ -0,0 +1,83 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var continueswitch_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/continueswitch-in.go", continueswitch_in_go_contents)
+
+func worker(handoff chan int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		worker(handoff)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := continueswitch_in_go_scope.EnteringNewChildScope()
+	scope.Declare("handoff", &handoff)
+	{
+		scope := scope.EnteringNewChildScope()
+		for i := 0; i < 2; i++ {
+			godebug.Line(ctx, scope, 4)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 5)
+			handoff <- i
+		}
+		godebug.Line(ctx, scope, 4)
+	}
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, continueswitch_in_go_scope, 10)
+	handoff := make(chan int)
+	scope := continueswitch_in_go_scope.EnteringNewChildScope()
+	scope.Declare("handoff", &handoff)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 11)
+	godebug.Line(ctx, scope, 12)
+
+	go worker(handoff)
+	godebug.Line(ctx, scope, 13)
+	a := <-handoff
+	scope.Declare("a", &a)
+	godebug.Line(ctx, scope, 14)
+	b := <-handoff
+	scope.Declare("b", &b)
+	godebug.Line(ctx, scope, 15)
+	println(a, b)
+}
+
+var continueswitch_in_go_contents = `package main
+
+func worker(handoff chan int) {
+	for i := 0; i < 2; i++ {
+		handoff <- i
+	}
+}
+
+func main() {
+	handoff := make(chan int)
+	_ = "breakpoint"
+	go worker(handoff)
+	a := <-handoff
+	b := <-handoff
+	println(a, b)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"worker": worker,
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,9 @@
+package main
+
+func main() {
+	small := []int{1, 2, 3}
+	big := make([]int, 20000)
+	_ = "breakpoint"
+	small[0], big[0] = 10, 10
+	println(small[0], big[0])
+}
@@ -0,0 +1,51 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var copybudget_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/copybudget-in.go", copybudget_in_go_contents)
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, copybudget_in_go_scope, 4)
+	small := []int{1, 2, 3}
+	scope := copybudget_in_go_scope.EnteringNewChildScope()
+	scope.Declare("small", &small)
+	godebug.Line(ctx, scope, 5)
+	big := make([]int, 20000)
+	scope.Declare("big", &big)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 6)
+	godebug.Line(ctx, scope, 7)
+
+	small[0], big[0] = 10, 10
+	godebug.Assigned(ctx, scope, 7)
+	godebug.Line(ctx, scope, 8)
+	println(small[0], big[0])
+}
+
+var copybudget_in_go_contents = `package main
+
+func main() {
+	small := []int{1, 2, 3}
+	big := make([]int, 20000)
+	_ = "breakpoint"
+	small[0], big[0] = 10, 10
+	println(small[0], big[0])
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,23 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+func handler(name string, done chan bool) {
+	godebug.LabelGoroutine(name)
+	_ = "breakpoint"
+	println("handled by", name)
+	done <- true
+}
+
+func main() {
+	godebug.SetDebuggableGoroutines(func(label string) bool {
+		return label == "handler"
+	})
+	done := make(chan bool)
+	go handler("background", done)
+	<-done
+	go handler("handler", done)
+	<-done
+	_ = "breakpoint"
+	println("done")
+}
@@ -0,0 +1,107 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var debuggable_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/debuggable-in.go", debuggable_in_go_contents)
+
+func handler(name string, done chan bool) {
+	ctx, ok := godebug.EnterFunc(func() {
+		handler(name, done)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := debuggable_in_go_scope.EnteringNewChildScope()
+	scope.Declare("name", &name, "done", &done)
+	godebug.Line(ctx, scope, 6)
+	godebug.LabelGoroutine(name)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 7)
+	godebug.Line(ctx, scope, 8)
+
+	println("handled by", name)
+	godebug.Line(ctx, scope, 9)
+	done <- true
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, debuggable_in_go_scope, 13)
+	godebug.SetDebuggableGoroutines(func(label string) bool {
+		var result1 bool
+		fn := func(ctx *godebug.Context) {
+			result1 = func() bool {
+				scope := debuggable_in_go_scope.EnteringNewChildScope()
+				scope.Declare("label", &label)
+				godebug.Line(ctx, scope, 14)
+				return label == "handler"
+			}()
+		}
+		if ctx, ok := godebug.EnterFuncLit(fn); ok {
+			defer godebug.ExitFunc(ctx, &result1)
+			fn(ctx)
+		}
+		return result1
+	},
+	)
+	godebug.Line(ctx, debuggable_in_go_scope, 16)
+	done := make(chan bool)
+	scope := debuggable_in_go_scope.EnteringNewChildScope()
+	scope.Declare("done", &done)
+	godebug.Line(ctx, scope, 17)
+	go handler("background", done)
+	godebug.Line(ctx, scope, 18)
+	<-done
+	godebug.Line(ctx, scope, 19)
+	go handler("handler", done)
+	godebug.Line(ctx, scope, 20)
+	<-done
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 21)
+	godebug.Line(ctx, scope, 22)
+
+	println("done")
+}
+
+var debuggable_in_go_contents = `package main
+
+import "github.com/mailgun/godebug/lib"
+
+func handler(name string, done chan bool) {
+	godebug.LabelGoroutine(name)
+	_ = "breakpoint"
+	println("handled by", name)
+	done <- true
+}
+
+func main() {
+	godebug.SetDebuggableGoroutines(func(label string) bool {
+		return label == "handler"
+	})
+	done := make(chan bool)
+	go handler("background", done)
+	<-done
+	go handler("handler", done)
+	<-done
+	_ = "breakpoint"
+	println("done")
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"handler": handler,
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,14 @@
+package main
+
+type Point struct {
+	X, Y int
+}
+
+func main() {
+	p := &Point{1, 2}
+	names := []string{"a", "b"}
+	ages := map[string]int{"b": 2, "a": 1}
+	var err error
+	_ = "breakpoint"
+	_, _, _, _ = p, names, ages, err
+}
@@ -0,0 +1,63 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var delve_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/delve-in.go", delve_in_go_contents)
+
+type Point struct {
+	X, Y int
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, delve_in_go_scope, 8)
+	p := &Point{1, 2}
+	scope := delve_in_go_scope.EnteringNewChildScope()
+	scope.Declare("p", &p)
+	godebug.Line(ctx, scope, 9)
+	names := []string{"a", "b"}
+	scope.Declare("names", &names)
+	godebug.Line(ctx, scope, 10)
+	ages := map[string]int{"b": 2, "a": 1}
+	scope.Declare("ages", &ages)
+	godebug.Line(ctx, scope, 11)
+	var err error
+	scope.Declare("err", &err)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 12)
+	godebug.Line(ctx, scope, 13)
+
+	_, _, _, _ = p, names, ages, err
+}
+
+var delve_in_go_contents = `package main
+
+type Point struct {
+	X, Y int
+}
+
+func main() {
+	p := &Point{1, 2}
+	names := []string{"a", "b"}
+	ages := map[string]int{"b": 2, "a": 1}
+	var err error
+	_ = "breakpoint"
+	_, _, _, _ = p, names, ages, err
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
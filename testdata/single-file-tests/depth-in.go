@@ -0,0 +1,13 @@
+package main
+
+func fact(n int) int {
+	if n <= 1 {
+		_ = "breakpoint"
+		return 1
+	}
+	return n * fact(n-1)
+}
+
+func main() {
+	println(fact(5))
+}
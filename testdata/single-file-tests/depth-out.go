@@ -0,0 +1,65 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var depth_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/depth-in.go", depth_in_go_contents)
+
+func fact(n int) (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = fact(n)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := depth_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 4)
+	if n <= 1 {
+		godebug.SetTraceGen(ctx)
+		godebug.Line(ctx, scope, 5)
+		godebug.Line(ctx, scope, 6)
+
+		return 1
+	}
+	godebug.Line(ctx, scope, 8)
+	return n * fact(n-1)
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, depth_in_go_scope, 12)
+	println(fact(5))
+}
+
+var depth_in_go_contents = `package main
+
+func fact(n int) int {
+	if n <= 1 {
+		_ = "breakpoint"
+		return 1
+	}
+	return n * fact(n-1)
+}
+
+func main() {
+	println(fact(5))
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"fact": fact,
+		"main": main,
+	}
+}
\ No newline at end of file
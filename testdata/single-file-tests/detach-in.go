@@ -0,0 +1,15 @@
+package main
+
+func main() {
+	sum := 0
+	_ = "breakpoint"
+	for i := 0; i < 5; i++ {
+		sum += add(i)
+		_ = "breakpoint"
+	}
+	println(sum)
+}
+
+func add(i int) int {
+	return i
+}
@@ -0,0 +1,80 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var detach_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "detach-in.go", detach_in_go_contents)
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, detach_in_go_scope, 4)
+	sum := 0
+	scope := detach_in_go_scope.EnteringNewChildScope()
+	scope.Declare("sum", &sum)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 5)
+	{
+		scope := scope.EnteringNewChildScope()
+
+		for i := 0; i < 5; i++ {
+			godebug.Line(ctx, scope, 6)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 7)
+			sum += add(i)
+			godebug.Assigned(ctx, scope, 7)
+			godebug.SetTraceGen(ctx)
+			godebug.Line(ctx, scope, 8)
+
+		}
+		godebug.Line(ctx, scope, 6)
+	}
+	godebug.Line(ctx, scope, 10)
+	println(sum)
+}
+
+func add(i int) (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = add(i)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := detach_in_go_scope.EnteringNewChildScope()
+	scope.Declare("i", &i)
+	godebug.Line(ctx, scope, 14)
+	return i
+}
+
+var detach_in_go_contents = `package main
+
+func main() {
+	sum := 0
+	_ = "breakpoint"
+	for i := 0; i < 5; i++ {
+		sum += add(i)
+		_ = "breakpoint"
+	}
+	println(sum)
+}
+
+func add(i int) int {
+	return i
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+		"add": add,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,18 @@
+package main
+
+func main() {
+	var queue []int
+	_ = "breakpoint"
+	for i := 0; i < 3; i++ {
+		queue = append(queue, i)
+		if i == 1 {
+			_ = "breakpoint"
+		}
+		record(i)
+	}
+	println(len(queue))
+}
+
+func record(i int) {
+	println("recorded", i)
+}
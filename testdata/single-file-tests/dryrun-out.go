@@ -0,0 +1,88 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var dryrun_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "dryrun-in.go", dryrun_in_go_contents)
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, dryrun_in_go_scope, 4)
+	var queue []int
+	scope := dryrun_in_go_scope.EnteringNewChildScope()
+	scope.Declare("queue", &queue)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 5)
+	{
+		scope := scope.EnteringNewChildScope()
+
+		for i := 0; i < 3; i++ {
+			godebug.Line(ctx, scope, 6)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 7)
+			queue = append(queue, i)
+			godebug.Assigned(ctx, scope, 7)
+			godebug.Line(ctx, scope, 8)
+			if i == 1 {
+				godebug.SetTraceGen(ctx)
+				godebug.Line(ctx, scope, 9)
+
+			}
+			godebug.Line(ctx, scope, 11)
+			record(i)
+		}
+		godebug.Line(ctx, scope, 6)
+	}
+	godebug.Line(ctx, scope, 13)
+	println(len(queue))
+}
+
+func record(i int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		record(i)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := dryrun_in_go_scope.EnteringNewChildScope()
+	scope.Declare("i", &i)
+	godebug.Line(ctx, scope, 17)
+	println("recorded", i)
+}
+
+var dryrun_in_go_contents = `package main
+
+func main() {
+	var queue []int
+	_ = "breakpoint"
+	for i := 0; i < 3; i++ {
+		queue = append(queue, i)
+		if i == 1 {
+			_ = "breakpoint"
+		}
+		record(i)
+	}
+	println(len(queue))
+}
+
+func record(i int) {
+	println("recorded", i)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+		"record": record,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,28 @@
+package main
+
+type Item struct {
+	Name string
+	Tags []string
+	n    int
+}
+
+type Order struct {
+	ID    int
+	Items []Item
+	Notes map[string]string
+	Next  *Order
+}
+
+func main() {
+	a := Order{ID: 1, Items: []Item{{Name: "x", Tags: []string{"red"}}, {Name: "y", n: 1}}, Notes: map[string]string{"k": "v"}}
+	b := a
+	b.Items = []Item{{Name: "x", Tags: []string{"red"}}, {Name: "y", n: 2}}
+	c := a
+	c.Notes = map[string]string{"k": "w"}
+	d := a
+	d.Next = &Order{ID: 2}
+	e := a
+	e.Items = append([]Item(nil), a.Items...)
+	_ = "breakpoint"
+	_, _, _, _, _ = a, b, c, d, e
+}
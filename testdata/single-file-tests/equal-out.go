@@ -0,0 +1,101 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var equal_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/equal-in.go", equal_in_go_contents)
+
+type Item struct {
+	Name string
+	Tags []string
+	n    int
+}
+
+type Order struct {
+	ID    int
+	Items []Item
+	Notes map[string]string
+	Next  *Order
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, equal_in_go_scope, 17)
+	a := Order{ID: 1, Items: []Item{{Name: "x", Tags: []string{"red"}}, {Name: "y", n: 1}}, Notes: map[string]string{"k": "v"}}
+	scope := equal_in_go_scope.EnteringNewChildScope()
+	scope.Declare("a", &a)
+	godebug.Line(ctx, scope, 18)
+	b := a
+	scope.Declare("b", &b)
+	godebug.Line(ctx, scope, 19)
+	b.Items = []Item{{Name: "x", Tags: []string{"red"}}, {Name: "y", n: 2}}
+	godebug.Assigned(ctx, scope, 19)
+	godebug.Line(ctx, scope, 20)
+	c := a
+	scope.Declare("c", &c)
+	godebug.Line(ctx, scope, 21)
+	c.Notes = map[string]string{"k": "w"}
+	godebug.Assigned(ctx, scope, 21)
+	godebug.Line(ctx, scope, 22)
+	d := a
+	scope.Declare("d", &d)
+	godebug.Line(ctx, scope, 23)
+	d.Next = &Order{ID: 2}
+	godebug.Assigned(ctx, scope, 23)
+	godebug.Line(ctx, scope, 24)
+	e := a
+	scope.Declare("e", &e)
+	godebug.Line(ctx, scope, 25)
+	e.Items = append([]Item(nil), a.Items...)
+	godebug.Assigned(ctx, scope, 25)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 26)
+	godebug.Line(ctx, scope, 27)
+
+	_, _, _, _, _ = a, b, c, d, e
+}
+
+var equal_in_go_contents = `package main
+
+type Item struct {
+	Name string
+	Tags []string
+	n    int
+}
+
+type Order struct {
+	ID    int
+	Items []Item
+	Notes map[string]string
+	Next  *Order
+}
+
+func main() {
+	a := Order{ID: 1, Items: []Item{{Name: "x", Tags: []string{"red"}}, {Name: "y", n: 1}}, Notes: map[string]string{"k": "v"}}
+	b := a
+	b.Items = []Item{{Name: "x", Tags: []string{"red"}}, {Name: "y", n: 2}}
+	c := a
+	c.Notes = map[string]string{"k": "w"}
+	d := a
+	d.Next = &Order{ID: 2}
+	e := a
+	e.Items = append([]Item(nil), a.Items...)
+	_ = "breakpoint"
+	_, _, _, _, _ = a, b, c, d, e
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
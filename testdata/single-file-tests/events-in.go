@@ -0,0 +1,15 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+func main() {
+	events := godebug.Events()
+	x := 1
+	_ = "breakpoint"
+	x++
+	x *= 10
+	for len(events) > 0 {
+		e := <-events
+		println(e.Line, e.Source, e.Locals["x"].(int))
+	}
+}
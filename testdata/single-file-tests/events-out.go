@@ -0,0 +1,68 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var events_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/events-in.go", events_in_go_contents)
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, events_in_go_scope, 6)
+	events := godebug.Events()
+	scope := events_in_go_scope.EnteringNewChildScope()
+	scope.Declare("events", &events)
+	godebug.Line(ctx, scope, 7)
+	x := 1
+	scope.Declare("x", &x)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 8)
+	godebug.Line(ctx, scope, 9)
+
+	x++
+	godebug.Assigned(ctx, scope, 9)
+	godebug.Line(ctx, scope, 10)
+	x *= 10
+	godebug.Assigned(ctx, scope, 10)
+	godebug.Line(ctx, scope, 11)
+	for len(events) > 0 {
+		godebug.Line(ctx, scope, 12)
+		e := <-events
+		scope := scope.EnteringNewChildScope()
+		scope.Declare("e", &e)
+		godebug.Line(ctx, scope, 13)
+		println(e.Line, e.Source, e.Locals["x"].(int))
+		godebug.Line(ctx, scope, 11)
+	}
+}
+
+var events_in_go_contents = `package main
+
+import "github.com/mailgun/godebug/lib"
+
+func main() {
+	events := godebug.Events()
+	x := 1
+	_ = "breakpoint"
+	x++
+	x *= 10
+	for len(events) > 0 {
+		e := <-events
+		println(e.Line, e.Source, e.Locals["x"].(int))
+	}
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -5,7 +5,7 @@ import (
 	"github.com/mailgun/godebug/lib"
 )
 
-var example_in_go_scope = godebug.EnteringNewFile(main_pkg_scope, example_in_go_contents)
+var example_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/example-in.go", example_in_go_contents)
 
 func main() {
 	ctx, ok := godebug.EnterFunc(main)
@@ -21,6 +21,7 @@ func main() {
 	godebug.Line(ctx, scope, 8)
 
 	x = mul(x, x)
+	godebug.Assigned(ctx, scope, 8)
 	godebug.Line(ctx, scope, 9)
 	if x == 4 {
 		godebug.Line(ctx, scope, 10)
@@ -40,15 +41,14 @@ func main() {
 	}
 }
 
-func add(n, m int) int {
-	var result1 int
+func add(n, m int) (result1 int) {
 	ctx, ok := godebug.EnterFunc(func() {
 		result1 = add(n, m)
 	})
 	if !ok {
 		return result1
 	}
-	defer godebug.ExitFunc(ctx)
+	defer godebug.ExitFunc(ctx, &result1)
 	scope := example_in_go_scope.EnteringNewChildScope()
 	scope.Declare("n", &n, "m", &m)
 	godebug.Line(ctx, scope, 19)
@@ -65,15 +65,14 @@ func add(n, m int) int {
 	return n + m
 }
 
-func mul(n, m int) int {
-	var result1 int
+func mul(n, m int) (result1 int) {
 	ctx, ok := godebug.EnterFunc(func() {
 		result1 = mul(n, m)
 	})
 	if !ok {
 		return result1
 	}
-	defer godebug.ExitFunc(ctx)
+	defer godebug.ExitFunc(ctx, &result1)
 	scope := example_in_go_scope.EnteringNewChildScope()
 	scope.Declare("n", &n, "m", &m)
 	godebug.Line(ctx, scope, 29)
@@ -86,6 +85,7 @@ func mul(n, m int) int {
 			scope.Declare("i", &i)
 			godebug.Line(ctx, scope, 31)
 			x = add(x, m)
+			godebug.Assigned(ctx, scope, 31)
 		}
 		godebug.Line(ctx, scope, 30)
 	}
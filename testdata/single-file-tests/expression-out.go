@@ -2,23 +2,21 @@ package main
 
 import "github.com/mailgun/godebug/lib"
 
-var expression_in_go_scope = godebug.EnteringNewFile(main_pkg_scope, expression_in_go_contents)
+var expression_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/expression-in.go", expression_in_go_contents)
 
 type Foo struct {
 	A int
 	B []string
 }
 
-func plusTwo(x int) (int, string) {
-	var result1 int
-	var result2 string
+func plusTwo(x int) (result1 int, result2 string) {
 	ctx, ok := godebug.EnterFunc(func() {
 		result1, result2 = plusTwo(x)
 	})
 	if !ok {
 		return result1, result2
 	}
-	defer godebug.ExitFunc(ctx)
+	defer godebug.ExitFunc(ctx, &result1, &result2)
 	scope := expression_in_go_scope.EnteringNewChildScope()
 	scope.Declare("x", &x)
 	godebug.Line(ctx, scope, 9)
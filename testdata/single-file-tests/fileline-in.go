@@ -0,0 +1,12 @@
+package main
+
+func main() {
+	_ = "breakpoint"
+	for i := 0; i < 2; i++ {
+		greet(i)
+	}
+}
+
+func greet(i int) {
+	println("hello", i)
+}
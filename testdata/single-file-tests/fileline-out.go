@@ -0,0 +1,67 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var fileline_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "fileline-in.go", fileline_in_go_contents)
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, fileline_in_go_scope, 4)
+	{
+		scope := fileline_in_go_scope.EnteringNewChildScope()
+
+		for i := 0; i < 2; i++ {
+			godebug.Line(ctx, scope, 5)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 6)
+			greet(i)
+		}
+		godebug.Line(ctx, scope, 5)
+	}
+}
+
+func greet(i int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		greet(i)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := fileline_in_go_scope.EnteringNewChildScope()
+	scope.Declare("i", &i)
+	godebug.Line(ctx, scope, 11)
+	println("hello", i)
+}
+
+var fileline_in_go_contents = `package main
+
+func main() {
+	_ = "breakpoint"
+	for i := 0; i < 2; i++ {
+		greet(i)
+	}
+}
+
+func greet(i int) {
+	println("hello", i)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+		"greet": greet,
+	}
+}
\ No newline at end of file
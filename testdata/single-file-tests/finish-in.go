@@ -0,0 +1,38 @@
+package main
+
+func fib(n int) int {
+	if n < 2 {
+		return n
+	}
+	a := fib(n - 1)
+	b := fib(n - 2)
+	return a + b
+}
+
+func divmod(a, b int) (q, r int) {
+	q = a / b
+	r = a % b
+	return
+}
+
+func fact(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	return n * fact(n-1)
+}
+
+func note(s string) {
+	println(s)
+}
+
+func main() {
+	_ = "breakpoint"
+	x := fib(3)
+	q, r := divmod(x, 2)
+	note("done")
+	half := func(n int) int {
+		return n / 2
+	}
+	println(x, q, r, half(x), fact(3))
+}
@@ -0,0 +1,180 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var finish_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/finish-in.go", finish_in_go_contents)
+
+func fib(n int) (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = fib(n)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := finish_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 4)
+	if n < 2 {
+		godebug.Line(ctx, scope, 5)
+		return n
+	}
+	godebug.Line(ctx, scope, 7)
+	a := fib(n - 1)
+	scope.Declare("a", &a)
+	godebug.Line(ctx, scope, 8)
+	b := fib(n - 2)
+	scope.Declare("b", &b)
+	godebug.Line(ctx, scope, 9)
+	return a + b
+}
+
+func divmod(a, b int) (q, r int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		q, r = divmod(a, b)
+	})
+	if !ok {
+		return q, r
+	}
+	defer godebug.ExitFunc(ctx, &q, &r)
+	scope := finish_in_go_scope.EnteringNewChildScope()
+	scope.Declare("a", &a, "b", &b, "q", &q, "r", &r)
+	godebug.Line(ctx, scope, 13)
+	q = a / b
+	godebug.Assigned(ctx, scope, 13)
+	godebug.Line(ctx, scope, 14)
+	r = a % b
+	godebug.Assigned(ctx, scope, 14)
+	godebug.Line(ctx, scope, 15)
+	return
+}
+
+func fact(n int) (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = fact(n)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := finish_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 19)
+	if n <= 1 {
+		godebug.Line(ctx, scope, 20)
+		return 1
+	}
+	godebug.Line(ctx, scope, 22)
+	return n * fact(n-1)
+}
+
+func note(s string) {
+	ctx, ok := godebug.EnterFunc(func() {
+		note(s)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := finish_in_go_scope.EnteringNewChildScope()
+	scope.Declare("s", &s)
+	godebug.Line(ctx, scope, 26)
+	println(s)
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, finish_in_go_scope, 30)
+	godebug.Line(ctx, finish_in_go_scope, 31)
+
+	x := fib(3)
+	scope := finish_in_go_scope.EnteringNewChildScope()
+	scope.Declare("x", &x)
+	godebug.Line(ctx, scope, 32)
+	q, r := divmod(x, 2)
+	scope.Declare("q", &q, "r", &r)
+	godebug.Line(ctx, scope, 33)
+	note("done")
+	godebug.Line(ctx, scope, 34)
+	half := func(n int) int {
+		var result1 int
+		fn := func(ctx *godebug.Context) {
+			result1 = func() int {
+				scope := scope.EnteringNewChildScope()
+				scope.Declare("n", &n)
+				godebug.Line(ctx, scope, 35)
+				return n / 2
+			}()
+		}
+		if ctx, ok := godebug.EnterFuncLit(fn); ok {
+			defer godebug.ExitFunc(ctx, &result1)
+			fn(ctx)
+		}
+		return result1
+	}
+	scope.Declare("half", &half)
+	godebug.Line(ctx, scope, 37)
+
+	println(x, q, r, half(x), fact(3))
+}
+
+var finish_in_go_contents = `package main
+
+func fib(n int) int {
+	if n < 2 {
+		return n
+	}
+	a := fib(n - 1)
+	b := fib(n - 2)
+	return a + b
+}
+
+func divmod(a, b int) (q, r int) {
+	q = a / b
+	r = a % b
+	return
+}
+
+func fact(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	return n * fact(n-1)
+}
+
+func note(s string) {
+	println(s)
+}
+
+func main() {
+	_ = "breakpoint"
+	x := fib(3)
+	q, r := divmod(x, 2)
+	note("done")
+	half := func(n int) int {
+		return n / 2
+	}
+	println(x, q, r, half(x), fact(3))
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"fib": fib,
+		"divmod": divmod,
+		"fact": fact,
+		"note": note,
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,18 @@
+package main
+
+type Mode uint32
+
+const (
+	ModeRead Mode = 1 << iota
+	ModeWrite
+	ModeExec
+
+	ModeAll = ModeRead | ModeWrite | ModeExec
+)
+
+func main() {
+	m := ModeRead | ModeExec
+	n := 16
+	_ = "breakpoint"
+	_, _ = m, n
+}
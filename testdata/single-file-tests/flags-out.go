@@ -0,0 +1,71 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var flags_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/flags-in.go", flags_in_go_contents)
+
+type Mode uint32
+
+const (
+	ModeRead Mode = 1 << iota
+	ModeWrite
+	ModeExec
+
+	ModeAll = ModeRead | ModeWrite | ModeExec
+)
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, flags_in_go_scope, 14)
+	m := ModeRead | ModeExec
+	scope := flags_in_go_scope.EnteringNewChildScope()
+	scope.Declare("m", &m)
+	godebug.Line(ctx, scope, 15)
+	n := 16
+	scope.Declare("n", &n)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 16)
+	godebug.Line(ctx, scope, 17)
+
+	_, _ = m, n
+}
+
+var flags_in_go_contents = `package main
+
+type Mode uint32
+
+const (
+	ModeRead Mode = 1 << iota
+	ModeWrite
+	ModeExec
+
+	ModeAll = ModeRead | ModeWrite | ModeExec
+)
+
+func main() {
+	m := ModeRead | ModeExec
+	n := 16
+	_ = "breakpoint"
+	_, _ = m, n
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+		"ModeRead": ModeRead,
+		"ModeWrite": ModeWrite,
+		"ModeExec": ModeExec,
+		"ModeAll": ModeAll,
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
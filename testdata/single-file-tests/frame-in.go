@@ -0,0 +1,18 @@
+package main
+
+func fact(n int) int {
+	if n <= 1 {
+		_ = "breakpoint"
+		return 1
+	}
+	rest := fact(n - 1)
+	return n * rest
+}
+
+func main() {
+	total := 0
+	for i := 2; i <= 3; i++ {
+		total += fact(i)
+	}
+	println(total)
+}
@@ -0,0 +1,88 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var frame_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/frame-in.go", frame_in_go_contents)
+
+func fact(n int) (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = fact(n)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := frame_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 4)
+	if n <= 1 {
+		godebug.SetTraceGen(ctx)
+		godebug.Line(ctx, scope, 5)
+		godebug.Line(ctx, scope, 6)
+
+		return 1
+	}
+	godebug.Line(ctx, scope, 8)
+	rest := fact(n - 1)
+	scope.Declare("rest", &rest)
+	godebug.Line(ctx, scope, 9)
+	return n * rest
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, frame_in_go_scope, 13)
+	total := 0
+	scope := frame_in_go_scope.EnteringNewChildScope()
+	scope.Declare("total", &total)
+	{
+		scope := scope.EnteringNewChildScope()
+		for i := 2; i <= 3; i++ {
+			godebug.Line(ctx, scope, 14)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 15)
+			total += fact(i)
+			godebug.Assigned(ctx, scope, 15)
+		}
+		godebug.Line(ctx, scope, 14)
+	}
+	godebug.Line(ctx, scope, 17)
+	println(total)
+}
+
+var frame_in_go_contents = `package main
+
+func fact(n int) int {
+	if n <= 1 {
+		_ = "breakpoint"
+		return 1
+	}
+	rest := fact(n - 1)
+	return n * rest
+}
+
+func main() {
+	total := 0
+	for i := 2; i <= 3; i++ {
+		total += fact(i)
+	}
+	println(total)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"fact": fact,
+		"main": main,
+	}
+}
\ No newline at end of file
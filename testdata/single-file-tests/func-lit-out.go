@@ -5,7 +5,7 @@ import (
 	"github.com/mailgun/godebug/lib"
 )
 
-var func_lit_in_go_scope = godebug.EnteringNewFile(main_pkg_scope, func_lit_in_go_contents)
+var func_lit_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/func-lit-in.go", func_lit_in_go_contents)
 
 func main() {
 	ctx, ok := godebug.EnterFunc(main)
@@ -33,7 +33,7 @@ var foo = func(a, _ int) (b, _ string) {
 		}()
 	}
 	if ctx, ok := godebug.EnterFuncLit(fn); ok {
-		defer godebug.ExitFunc(ctx)
+		defer godebug.ExitFunc(ctx, &b, &result2)
 		fn(ctx)
 	}
 	return b, result2
@@ -0,0 +1,15 @@
+package main
+
+func worker(ready chan bool) {
+	n := 0
+	ready <- true
+	for { n++ }
+}
+
+func main() {
+	ready := make(chan bool)
+	go worker(ready)
+	<-ready
+	_ = "breakpoint"
+	_ = "done"
+}
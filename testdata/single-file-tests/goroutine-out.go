@@ -0,0 +1,80 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var goroutine_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/goroutine-in.go", goroutine_in_go_contents)
+
+func worker(ready chan bool) {
+	ctx, ok := godebug.EnterFunc(func() {
+		worker(ready)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := goroutine_in_go_scope.EnteringNewChildScope()
+	scope.Declare("ready", &ready)
+	godebug.Line(ctx, scope, 4)
+	n := 0
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 5)
+	ready <- true
+	godebug.Line(ctx, scope, 6)
+	for {
+		godebug.Line(ctx, scope, 6)
+		n++
+		godebug.Assigned(ctx, scope, 6)
+		godebug.Line(ctx, scope, 6)
+	}
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, goroutine_in_go_scope, 10)
+	ready := make(chan bool)
+	scope := goroutine_in_go_scope.EnteringNewChildScope()
+	scope.Declare("ready", &ready)
+	godebug.Line(ctx, scope, 11)
+	go worker(ready)
+	godebug.Line(ctx, scope, 12)
+	<-ready
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 13)
+	godebug.Line(ctx, scope, 14)
+
+	_ = "done"
+}
+
+var goroutine_in_go_contents = `package main
+
+func worker(ready chan bool) {
+	n := 0
+	ready <- true
+	for { n++ }
+}
+
+func main() {
+	ready := make(chan bool)
+	go worker(ready)
+	<-ready
+	_ = "breakpoint"
+	_ = "done"
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"worker": worker,
+		"main": main,
+	}
+}
\ No newline at end of file
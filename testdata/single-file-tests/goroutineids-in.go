@@ -0,0 +1,24 @@
+package main
+
+func worker(done chan bool) {
+	done <- true
+}
+
+func spin(ready chan bool) {
+	n := 0
+	ready <- true
+	for { n++ }
+}
+
+func main() {
+	_ = "breakpoint"
+	done := make(chan bool)
+	go worker(done)
+	<-done
+	go worker(done)
+	<-done
+	go spin(done)
+	<-done
+	_ = "breakpoint"
+	_ = "done"
+}
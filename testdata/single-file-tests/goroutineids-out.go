@@ -0,0 +1,115 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var goroutineids_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/goroutineids-in.go", goroutineids_in_go_contents)
+
+func worker(done chan bool) {
+	ctx, ok := godebug.EnterFunc(func() {
+		worker(done)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := goroutineids_in_go_scope.EnteringNewChildScope()
+	scope.Declare("done", &done)
+	godebug.Line(ctx, scope, 4)
+	done <- true
+}
+
+func spin(ready chan bool) {
+	ctx, ok := godebug.EnterFunc(func() {
+		spin(ready)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := goroutineids_in_go_scope.EnteringNewChildScope()
+	scope.Declare("ready", &ready)
+	godebug.Line(ctx, scope, 8)
+	n := 0
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 9)
+	ready <- true
+	godebug.Line(ctx, scope, 10)
+	for {
+		godebug.Line(ctx, scope, 10)
+		n++
+		godebug.Assigned(ctx, scope, 10)
+		godebug.Line(ctx, scope, 10)
+	}
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, goroutineids_in_go_scope, 14)
+	godebug.Line(ctx, goroutineids_in_go_scope, 15)
+
+	done := make(chan bool)
+	scope := goroutineids_in_go_scope.EnteringNewChildScope()
+	scope.Declare("done", &done)
+	godebug.Line(ctx, scope, 16)
+	go worker(done)
+	godebug.Line(ctx, scope, 17)
+	<-done
+	godebug.Line(ctx, scope, 18)
+	go worker(done)
+	godebug.Line(ctx, scope, 19)
+	<-done
+	godebug.Line(ctx, scope, 20)
+	go spin(done)
+	godebug.Line(ctx, scope, 21)
+	<-done
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 22)
+	godebug.Line(ctx, scope, 23)
+
+	_ = "done"
+}
+
+var goroutineids_in_go_contents = `package main
+
+func worker(done chan bool) {
+	done <- true
+}
+
+func spin(ready chan bool) {
+	n := 0
+	ready <- true
+	for { n++ }
+}
+
+func main() {
+	_ = "breakpoint"
+	done := make(chan bool)
+	go worker(done)
+	<-done
+	go worker(done)
+	<-done
+	go spin(done)
+	<-done
+	_ = "breakpoint"
+	_ = "done"
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"worker": worker,
+		"spin": spin,
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,26 @@
+package main
+
+type Point struct {
+	X, Y  int
+	Label string
+}
+
+type Node struct {
+	Name string
+	Next *Node
+}
+
+func main() {
+	p := Point{X: 1, Y: 2}
+	pp := &p
+	scores := map[string]float64{"b": 2, "a": 0.5}
+	var ids []int8
+	list := []interface{}{int8(3), "x", nil}
+	n := &Node{Name: "a"}
+	n.Next = n
+	x := 7
+	px := &x
+	f := main
+	_ = "breakpoint"
+	_, _, _, _, _, _, _, _ = pp, scores, ids, list, n, px, f, x
+}
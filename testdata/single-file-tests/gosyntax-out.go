@@ -0,0 +1,99 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var gosyntax_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/gosyntax-in.go", gosyntax_in_go_contents)
+
+type Point struct {
+	X, Y  int
+	Label string
+}
+
+type Node struct {
+	Name string
+	Next *Node
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, gosyntax_in_go_scope, 14)
+	p := Point{X: 1, Y: 2}
+	scope := gosyntax_in_go_scope.EnteringNewChildScope()
+	scope.Declare("p", &p)
+	godebug.Line(ctx, scope, 15)
+	pp := &p
+	scope.Declare("pp", &pp)
+	godebug.Line(ctx, scope, 16)
+	scores := map[string]float64{"b": 2, "a": 0.5}
+	scope.Declare("scores", &scores)
+	godebug.Line(ctx, scope, 17)
+	var ids []int8
+	scope.Declare("ids", &ids)
+	godebug.Line(ctx, scope, 18)
+	list := []interface{}{int8(3), "x", nil}
+	scope.Declare("list", &list)
+	godebug.Line(ctx, scope, 19)
+	n := &Node{Name: "a"}
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 20)
+	n.Next = n
+	godebug.Assigned(ctx, scope, 20)
+	godebug.Line(ctx, scope, 21)
+	x := 7
+	scope.Declare("x", &x)
+	godebug.Line(ctx, scope, 22)
+	px := &x
+	scope.Declare("px", &px)
+	godebug.Line(ctx, scope, 23)
+	f := main
+	scope.Declare("f", &f)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 24)
+	godebug.Line(ctx, scope, 25)
+
+	_, _, _, _, _, _, _, _ = pp, scores, ids, list, n, px, f, x
+}
+
+var gosyntax_in_go_contents = `package main
+
+type Point struct {
+	X, Y  int
+	Label string
+}
+
+type Node struct {
+	Name string
+	Next *Node
+}
+
+func main() {
+	p := Point{X: 1, Y: 2}
+	pp := &p
+	scores := map[string]float64{"b": 2, "a": 0.5}
+	var ids []int8
+	list := []interface{}{int8(3), "x", nil}
+	n := &Node{Name: "a"}
+	n.Next = n
+	x := 7
+	px := &x
+	f := main
+	_ = "breakpoint"
+	_, _, _, _, _, _, _, _ = pp, scores, ids, list, n, px, f, x
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
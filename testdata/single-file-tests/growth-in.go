@@ -0,0 +1,16 @@
+package main
+
+func main() {
+	var queue []int
+	seen := map[int]bool{}
+	n := 0
+	_ = "breakpoint"
+	for i := 0; i < 8; i++ {
+		queue = append(queue, i)
+		seen[i] = true
+		if len(queue) > 3 {
+			queue = queue[:1]
+		}
+	}
+	println(len(queue), len(seen), n)
+}
@@ -0,0 +1,78 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var growth_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "growth-in.go", growth_in_go_contents)
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, growth_in_go_scope, 4)
+	var queue []int
+	scope := growth_in_go_scope.EnteringNewChildScope()
+	scope.Declare("queue", &queue)
+	godebug.Line(ctx, scope, 5)
+	seen := map[int]bool{}
+	scope.Declare("seen", &seen)
+	godebug.Line(ctx, scope, 6)
+	n := 0
+	scope.Declare("n", &n)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 7)
+	{
+		scope := scope.EnteringNewChildScope()
+
+		for i := 0; i < 8; i++ {
+			godebug.Line(ctx, scope, 8)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 9)
+			queue = append(queue, i)
+			godebug.Assigned(ctx, scope, 9)
+			godebug.Line(ctx, scope, 10)
+			seen[i] = true
+			godebug.Assigned(ctx, scope, 10)
+			godebug.Line(ctx, scope, 11)
+			if len(queue) > 3 {
+				godebug.Line(ctx, scope, 12)
+				queue = queue[:1]
+				godebug.Assigned(ctx, scope, 12)
+			}
+		}
+		godebug.Line(ctx, scope, 8)
+	}
+	godebug.Line(ctx, scope, 15)
+	println(len(queue), len(seen), n)
+}
+
+var growth_in_go_contents = `package main
+
+func main() {
+	var queue []int
+	seen := map[int]bool{}
+	n := 0
+	_ = "breakpoint"
+	for i := 0; i < 8; i++ {
+		queue = append(queue, i)
+		seen[i] = true
+		if len(queue) > 3 {
+			queue = queue[:1]
+		}
+	}
+	println(len(queue), len(seen), n)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,15 @@
+package main
+
+func double(n int) int {
+	return n * 2
+}
+
+func main() {
+	x := 1
+	_ = "breakpoint"
+	x = 2
+	s := []int{x}
+	x = double(x)
+	s = append(s, x)
+	println(x, len(s))
+}
@@ -0,0 +1,78 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var history_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/history-in.go", history_in_go_contents)
+
+func double(n int) (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = double(n)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := history_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 4)
+	return n * 2
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, history_in_go_scope, 8)
+	x := 1
+	scope := history_in_go_scope.EnteringNewChildScope()
+	scope.Declare("x", &x)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 9)
+	godebug.Line(ctx, scope, 10)
+
+	x = 2
+	godebug.Assigned(ctx, scope, 10)
+	godebug.Line(ctx, scope, 11)
+	s := []int{x}
+	scope.Declare("s", &s)
+	godebug.Line(ctx, scope, 12)
+	x = double(x)
+	godebug.Assigned(ctx, scope, 12)
+	godebug.Line(ctx, scope, 13)
+	s = append(s, x)
+	godebug.Assigned(ctx, scope, 13)
+	godebug.Line(ctx, scope, 14)
+	println(x, len(s))
+}
+
+var history_in_go_contents = `package main
+
+func double(n int) int {
+	return n * 2
+}
+
+func main() {
+	x := 1
+	_ = "breakpoint"
+	x = 2
+	s := []int{x}
+	x = double(x)
+	s = append(s, x)
+	println(x, len(s))
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"double": double,
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,14 @@
+package main
+
+func square(n int) int {
+	return n * n
+}
+
+func main() {
+	total := 0
+	_ = "breakpoint"
+	for i := 0; i < 3; i++ {
+		total += square(i)
+	}
+	println(total)
+}
@@ -0,0 +1,76 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var hotspots_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/hotspots-in.go", hotspots_in_go_contents)
+
+func square(n int) (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = square(n)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := hotspots_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 4)
+	return n * n
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, hotspots_in_go_scope, 8)
+	total := 0
+	scope := hotspots_in_go_scope.EnteringNewChildScope()
+	scope.Declare("total", &total)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 9)
+	{
+		scope := scope.EnteringNewChildScope()
+
+		for i := 0; i < 3; i++ {
+			godebug.Line(ctx, scope, 10)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 11)
+			total += square(i)
+			godebug.Assigned(ctx, scope, 11)
+		}
+		godebug.Line(ctx, scope, 10)
+	}
+	godebug.Line(ctx, scope, 13)
+	println(total)
+}
+
+var hotspots_in_go_contents = `package main
+
+func square(n int) int {
+	return n * n
+}
+
+func main() {
+	total := 0
+	_ = "breakpoint"
+	for i := 0; i < 3; i++ {
+		total += square(i)
+	}
+	println(total)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"square": square,
+		"main": main,
+	}
+}
\ No newline at end of file
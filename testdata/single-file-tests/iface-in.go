@@ -0,0 +1,32 @@
+package main
+
+type shape interface {
+	area() int
+}
+
+type circle struct{ r int }
+
+func (c circle) area() int { return 3 * c.r * c.r }
+
+type square struct{ side int }
+
+func (s *square) area() int { return s.side * s.side }
+
+type stringer interface {
+	String() string
+}
+
+type celsius int
+
+func (c celsius) String() string { return "warm" }
+
+func main() {
+	var round shape = circle{r: 1}
+	var boxy shape = &square{side: 2}
+	var temp stringer = celsius(21)
+	var anything interface{} = "hello"
+	shapes := []shape{round, boxy}
+	var none shape
+	_ = "breakpoint"
+	println(round.area(), boxy.area(), temp.String(), anything != nil, len(shapes), none == nil)
+}
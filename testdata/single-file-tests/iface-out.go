@@ -0,0 +1,139 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var iface_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/iface-in.go", iface_in_go_contents)
+
+type shape interface {
+	area() int
+}
+
+type circle struct{ r int }
+
+func (c circle) area() (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = c.area()
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := iface_in_go_scope.EnteringNewChildScope()
+	scope.Declare("c", &c)
+	godebug.Line(ctx, scope, 9)
+	return 3 * c.r * c.r
+}
+
+type square struct{ side int }
+
+func (s *square) area() (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = s.area()
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := iface_in_go_scope.EnteringNewChildScope()
+	scope.Declare("s", &s)
+	godebug.Line(ctx, scope, 13)
+	return s.side * s.side
+}
+
+type stringer interface {
+	String() string
+}
+
+type celsius int
+
+func (c celsius) String() (result1 string) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = c.String()
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := iface_in_go_scope.EnteringNewChildScope()
+	scope.Declare("c", &c)
+	godebug.Line(ctx, scope, 21)
+	return "warm"
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, iface_in_go_scope, 24)
+	var round shape = circle{r: 1}
+	scope := iface_in_go_scope.EnteringNewChildScope()
+	scope.Declare("round", &round)
+	godebug.Line(ctx, scope, 25)
+	var boxy shape = &square{side: 2}
+	scope.Declare("boxy", &boxy)
+	godebug.Line(ctx, scope, 26)
+	var temp stringer = celsius(21)
+	scope.Declare("temp", &temp)
+	godebug.Line(ctx, scope, 27)
+	var anything interface{} = "hello"
+	scope.Declare("anything", &anything)
+	godebug.Line(ctx, scope, 28)
+	shapes := []shape{round, boxy}
+	scope.Declare("shapes", &shapes)
+	godebug.Line(ctx, scope, 29)
+	var none shape
+	scope.Declare("none", &none)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 30)
+	godebug.Line(ctx, scope, 31)
+
+	println(round.area(), boxy.area(), temp.String(), anything != nil, len(shapes), none == nil)
+}
+
+var iface_in_go_contents = `package main
+
+type shape interface {
+	area() int
+}
+
+type circle struct{ r int }
+
+func (c circle) area() int { return 3 * c.r * c.r }
+
+type square struct{ side int }
+
+func (s *square) area() int { return s.side * s.side }
+
+type stringer interface {
+	String() string
+}
+
+type celsius int
+
+func (c celsius) String() string { return "warm" }
+
+func main() {
+	var round shape = circle{r: 1}
+	var boxy shape = &square{side: 2}
+	var temp stringer = celsius(21)
+	var anything interface{} = "hello"
+	shapes := []shape{round, boxy}
+	var none shape
+	_ = "breakpoint"
+	println(round.area(), boxy.area(), temp.String(), anything != nil, len(shapes), none == nil)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
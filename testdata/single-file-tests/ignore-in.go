@@ -0,0 +1,15 @@
+package main
+
+func logf(msg string) {
+	_ = msg
+}
+
+func main() {
+	total := 0
+	_ = "breakpoint"
+	for i := 0; i < 2; i++ {
+		logf("adding")
+		total += i
+	}
+	println(total)
+}
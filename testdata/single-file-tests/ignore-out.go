@@ -0,0 +1,79 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var ignore_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/ignore-in.go", ignore_in_go_contents)
+
+func logf(msg string) {
+	ctx, ok := godebug.EnterFunc(func() {
+		logf(msg)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := ignore_in_go_scope.EnteringNewChildScope()
+	scope.Declare("msg", &msg)
+	godebug.Line(ctx, scope, 4)
+	_ = msg
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, ignore_in_go_scope, 8)
+	total := 0
+	scope := ignore_in_go_scope.EnteringNewChildScope()
+	scope.Declare("total", &total)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 9)
+	{
+		scope := scope.EnteringNewChildScope()
+
+		for i := 0; i < 2; i++ {
+			godebug.Line(ctx, scope, 10)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 11)
+			logf("adding")
+			godebug.Line(ctx, scope, 12)
+			total += i
+			godebug.Assigned(ctx, scope, 12)
+		}
+		godebug.Line(ctx, scope, 10)
+	}
+	godebug.Line(ctx, scope, 14)
+	println(total)
+}
+
+var ignore_in_go_contents = `package main
+
+func logf(msg string) {
+	_ = msg
+}
+
+func main() {
+	total := 0
+	_ = "breakpoint"
+	for i := 0; i < 2; i++ {
+		logf("adding")
+		total += i
+	}
+	println(total)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"logf": logf,
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,10 @@
+package main
+
+func main() {
+	total := 0
+	_ = "breakpoint"
+	for i := 0; i < 10; i++ {
+		total += i
+	}
+	println(total)
+}
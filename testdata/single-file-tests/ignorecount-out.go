@@ -0,0 +1,57 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var ignorecount_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/ignorecount-in.go", ignorecount_in_go_contents)
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, ignorecount_in_go_scope, 4)
+	total := 0
+	scope := ignorecount_in_go_scope.EnteringNewChildScope()
+	scope.Declare("total", &total)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 5)
+	{
+		scope := scope.EnteringNewChildScope()
+
+		for i := 0; i < 10; i++ {
+			godebug.Line(ctx, scope, 6)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 7)
+			total += i
+			godebug.Assigned(ctx, scope, 7)
+		}
+		godebug.Line(ctx, scope, 6)
+	}
+	godebug.Line(ctx, scope, 9)
+	println(total)
+}
+
+var ignorecount_in_go_contents = `package main
+
+func main() {
+	total := 0
+	_ = "breakpoint"
+	for i := 0; i < 10; i++ {
+		total += i
+	}
+	println(total)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
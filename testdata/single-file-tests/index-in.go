@@ -0,0 +1,18 @@
+package main
+
+type id string
+
+type user struct {
+	Tags []string
+}
+
+func main() {
+	xs := []int{10, 20, 30, 40}
+	arr := &[3]string{"a", "b", "c"}
+	m := map[string]int{"key": 1}
+	users := map[id]*user{"ann": {Tags: []string{"admin"}}}
+	byID := map[interface{}]string{1: "one"}
+	var i uint8 = 2
+	_ = "breakpoint"
+	println(xs[i], arr[0], m["key"], len(users), byID[1])
+}
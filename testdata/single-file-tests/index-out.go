@@ -0,0 +1,75 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var index_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/index-in.go", index_in_go_contents)
+
+type id string
+
+type user struct {
+	Tags []string
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, index_in_go_scope, 10)
+	xs := []int{10, 20, 30, 40}
+	scope := index_in_go_scope.EnteringNewChildScope()
+	scope.Declare("xs", &xs)
+	godebug.Line(ctx, scope, 11)
+	arr := &[3]string{"a", "b", "c"}
+	scope.Declare("arr", &arr)
+	godebug.Line(ctx, scope, 12)
+	m := map[string]int{"key": 1}
+	scope.Declare("m", &m)
+	godebug.Line(ctx, scope, 13)
+	users := map[id]*user{"ann": {Tags: []string{"admin"}}}
+	scope.Declare("users", &users)
+	godebug.Line(ctx, scope, 14)
+	byID := map[interface{}]string{1: "one"}
+	scope.Declare("byID", &byID)
+	godebug.Line(ctx, scope, 15)
+	var i uint8 = 2
+	scope.Declare("i", &i)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 16)
+	godebug.Line(ctx, scope, 17)
+
+	println(xs[i], arr[0], m["key"], len(users), byID[1])
+}
+
+var index_in_go_contents = `package main
+
+type id string
+
+type user struct {
+	Tags []string
+}
+
+func main() {
+	xs := []int{10, 20, 30, 40}
+	arr := &[3]string{"a", "b", "c"}
+	m := map[string]int{"key": 1}
+	users := map[id]*user{"ann": {Tags: []string{"admin"}}}
+	byID := map[interface{}]string{1: "one"}
+	var i uint8 = 2
+	_ = "breakpoint"
+	println(xs[i], arr[0], m["key"], len(users), byID[1])
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
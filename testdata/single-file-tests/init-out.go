@@ -2,7 +2,7 @@ package main
 
 import "github.com/mailgun/godebug/lib"
 
-var init_in_go_scope = godebug.EnteringNewFile(main_pkg_scope, init_in_go_contents)
+var init_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/init-in.go", init_in_go_contents)
 
 func init() {
 	a = 5
@@ -22,6 +22,7 @@ func (f *Foo) init() {
 	scope.Declare("f", &f)
 	godebug.Line(ctx, scope, 14)
 	*f = 1337
+	godebug.Assigned(ctx, scope, 14)
 }
 
 func main() {
@@ -0,0 +1,28 @@
+package main
+
+type address struct {
+	City string `json:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type user struct {
+	Name     string
+	Age      int
+	Tags     []string
+	Home     *address
+	password string
+}
+
+type job struct {
+	Name string
+	Phase complex128
+}
+
+func main() {
+	u := user{Name: "Ada <ada@example.com>", Age: 36, Tags: []string{"admin"}, Home: &address{City: "London"}, password: "hunter2"}
+	scores := map[string]float64{"math": 9.5, "art": 7}
+	j := job{Name: "build", Phase: 1 + 2i}
+	var nobody *user
+	_ = "breakpoint"
+	println(u.Name, len(scores), j.Name, nobody == nil)
+}
@@ -0,0 +1,91 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var json_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/json-in.go", json_in_go_contents)
+
+type address struct {
+	City string `json:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type user struct {
+	Name     string
+	Age      int
+	Tags     []string
+	Home     *address
+	password string
+}
+
+type job struct {
+	Name  string
+	Phase complex128
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, json_in_go_scope, 22)
+	u := user{Name: "Ada <ada@example.com>", Age: 36, Tags: []string{"admin"}, Home: &address{City: "London"}, password: "hunter2"}
+	scope := json_in_go_scope.EnteringNewChildScope()
+	scope.Declare("u", &u)
+	godebug.Line(ctx, scope, 23)
+	scores := map[string]float64{"math": 9.5, "art": 7}
+	scope.Declare("scores", &scores)
+	godebug.Line(ctx, scope, 24)
+	j := job{Name: "build", Phase: 1 + 2i}
+	scope.Declare("j", &j)
+	godebug.Line(ctx, scope, 25)
+	var nobody *user
+	scope.Declare("nobody", &nobody)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 26)
+	godebug.Line(ctx, scope, 27)
+
+	println(u.Name, len(scores), j.Name, nobody == nil)
+}
+
+var json_in_go_contents = `package main
+
+type address struct {
+	City string ` + "`" + `json:"city"` + "`" + `
+	Zip  string ` + "`" + `json:"zip,omitempty"` + "`" + `
+}
+
+type user struct {
+	Name     string
+	Age      int
+	Tags     []string
+	Home     *address
+	password string
+}
+
+type job struct {
+	Name string
+	Phase complex128
+}
+
+func main() {
+	u := user{Name: "Ada <ada@example.com>", Age: 36, Tags: []string{"admin"}, Home: &address{City: "London"}, password: "hunter2"}
+	scores := map[string]float64{"math": 9.5, "art": 7}
+	j := job{Name: "build", Phase: 1 + 2i}
+	var nobody *user
+	_ = "breakpoint"
+	println(u.Name, len(scores), j.Name, nobody == nil)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
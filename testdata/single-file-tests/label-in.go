@@ -0,0 +1,18 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+func worker(ready chan bool) {
+	godebug.LabelGoroutine("worker")
+	n := 0
+	ready <- true
+	for { n++ }
+}
+
+func main() {
+	ready := make(chan bool)
+	go worker(ready)
+	<-ready
+	_ = "breakpoint"
+	_ = "done"
+}
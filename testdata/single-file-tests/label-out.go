@@ -0,0 +1,85 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var label_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/label-in.go", label_in_go_contents)
+
+func worker(ready chan bool) {
+	ctx, ok := godebug.EnterFunc(func() {
+		worker(ready)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := label_in_go_scope.EnteringNewChildScope()
+	scope.Declare("ready", &ready)
+	godebug.Line(ctx, scope, 6)
+	godebug.LabelGoroutine("worker")
+	godebug.Line(ctx, scope, 7)
+	n := 0
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 8)
+	ready <- true
+	godebug.Line(ctx, scope, 9)
+	for {
+		godebug.Line(ctx, scope, 9)
+		n++
+		godebug.Assigned(ctx, scope, 9)
+		godebug.Line(ctx, scope, 9)
+	}
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, label_in_go_scope, 13)
+	ready := make(chan bool)
+	scope := label_in_go_scope.EnteringNewChildScope()
+	scope.Declare("ready", &ready)
+	godebug.Line(ctx, scope, 14)
+	go worker(ready)
+	godebug.Line(ctx, scope, 15)
+	<-ready
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 16)
+	godebug.Line(ctx, scope, 17)
+
+	_ = "done"
+}
+
+var label_in_go_contents = `package main
+
+import "github.com/mailgun/godebug/lib"
+
+func worker(ready chan bool) {
+	godebug.LabelGoroutine("worker")
+	n := 0
+	ready <- true
+	for { n++ }
+}
+
+func main() {
+	ready := make(chan bool)
+	go worker(ready)
+	<-ready
+	_ = "breakpoint"
+	_ = "done"
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"worker": worker,
+		"main": main,
+	}
+}
\ No newline at end of file
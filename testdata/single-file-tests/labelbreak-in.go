@@ -0,0 +1,25 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+func worker(name string, jobs chan int, done chan bool) {
+	godebug.LabelGoroutine(name)
+	for j := range jobs {
+		println(name, j)
+	}
+	done <- true
+}
+
+func main() {
+	a, b := make(chan int), make(chan int)
+	done := make(chan bool)
+	_ = "breakpoint"
+	go worker("a", a, done)
+	go worker("b", b, done)
+	a <- 1
+	close(a)
+	<-done
+	b <- 2
+	close(b)
+	<-done
+}
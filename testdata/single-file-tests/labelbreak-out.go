@@ -0,0 +1,105 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var labelbreak_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/labelbreak-in.go", labelbreak_in_go_contents)
+
+func worker(name string, jobs chan int, done chan bool) {
+	ctx, ok := godebug.EnterFunc(func() {
+		worker(name, jobs, done)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := labelbreak_in_go_scope.EnteringNewChildScope()
+	scope.Declare("name", &name, "jobs", &jobs, "done", &done)
+	godebug.Line(ctx, scope, 6)
+	godebug.LabelGoroutine(name)
+	{
+		scope := scope.EnteringNewChildScope()
+		for j := range jobs {
+			godebug.Line(ctx, scope, 7)
+			scope.Declare("j", &j)
+			godebug.Line(ctx, scope, 8)
+			println(name, j)
+		}
+		godebug.Line(ctx, scope, 7)
+	}
+	godebug.Line(ctx, scope, 10)
+	done <- true
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, labelbreak_in_go_scope, 14)
+	a, b := make(chan int), make(chan int)
+	scope := labelbreak_in_go_scope.EnteringNewChildScope()
+	scope.Declare("a", &a, "b", &b)
+	godebug.Line(ctx, scope, 15)
+	done := make(chan bool)
+	scope.Declare("done", &done)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 16)
+	godebug.Line(ctx, scope, 17)
+
+	go worker("a", a, done)
+	godebug.Line(ctx, scope, 18)
+	go worker("b", b, done)
+	godebug.Line(ctx, scope, 19)
+	a <- 1
+	godebug.Line(ctx, scope, 20)
+	close(a)
+	godebug.Line(ctx, scope, 21)
+	<-done
+	godebug.Line(ctx, scope, 22)
+	b <- 2
+	godebug.Line(ctx, scope, 23)
+	close(b)
+	godebug.Line(ctx, scope, 24)
+	<-done
+}
+
+var labelbreak_in_go_contents = `package main
+
+import "github.com/mailgun/godebug/lib"
+
+func worker(name string, jobs chan int, done chan bool) {
+	godebug.LabelGoroutine(name)
+	for j := range jobs {
+		println(name, j)
+	}
+	done <- true
+}
+
+func main() {
+	a, b := make(chan int), make(chan int)
+	done := make(chan bool)
+	_ = "breakpoint"
+	go worker("a", a, done)
+	go worker("b", b, done)
+	a <- 1
+	close(a)
+	<-done
+	b <- 2
+	close(b)
+	<-done
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"worker": worker,
+		"main": main,
+	}
+}
\ No newline at end of file
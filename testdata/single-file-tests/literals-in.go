@@ -0,0 +1,8 @@
+package main
+
+func main() {
+	i, f, s, b := -5, 3.14, "", true
+	var u uint64 = 18446744073709551615
+	_ = "breakpoint"
+	println(i, f, s, b, u)
+}
@@ -0,0 +1,47 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var literals_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "literals-in.go", literals_in_go_contents)
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, literals_in_go_scope, 4)
+	i, f, s, b := -5, 3.14, "", true
+	scope := literals_in_go_scope.EnteringNewChildScope()
+	scope.Declare("i", &i, "f", &f, "s", &s, "b", &b)
+	godebug.Line(ctx, scope, 5)
+	var u uint64 = 18446744073709551615
+	scope.Declare("u", &u)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 6)
+	godebug.Line(ctx, scope, 7)
+
+	println(i, f, s, b, u)
+}
+
+var literals_in_go_contents = `package main
+
+func main() {
+	i, f, s, b := -5, 3.14, "", true
+	var u uint64 = 18446744073709551615
+	_ = "breakpoint"
+	println(i, f, s, b, u)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,16 @@
+package main
+
+func main() {
+	var buf []int
+	total := 0
+	ratio := 1.0
+	done := false
+	_ = "breakpoint"
+	for i := 0; i < 5; i++ {
+		buf = append(buf, i)
+		total += i
+		ratio /= 2
+	}
+	done = true
+	println(len(buf), total, done)
+}
@@ -0,0 +1,81 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var mark_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/mark-in.go", mark_in_go_contents)
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, mark_in_go_scope, 4)
+	var buf []int
+	scope := mark_in_go_scope.EnteringNewChildScope()
+	scope.Declare("buf", &buf)
+	godebug.Line(ctx, scope, 5)
+	total := 0
+	scope.Declare("total", &total)
+	godebug.Line(ctx, scope, 6)
+	ratio := 1.0
+	scope.Declare("ratio", &ratio)
+	godebug.Line(ctx, scope, 7)
+	done := false
+	scope.Declare("done", &done)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 8)
+	{
+		scope := scope.EnteringNewChildScope()
+
+		for i := 0; i < 5; i++ {
+			godebug.Line(ctx, scope, 9)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 10)
+			buf = append(buf, i)
+			godebug.Assigned(ctx, scope, 10)
+			godebug.Line(ctx, scope, 11)
+			total += i
+			godebug.Assigned(ctx, scope, 11)
+			godebug.Line(ctx, scope, 12)
+			ratio /= 2
+			godebug.Assigned(ctx, scope, 12)
+		}
+		godebug.Line(ctx, scope, 9)
+	}
+	godebug.Line(ctx, scope, 14)
+	done = true
+	godebug.Assigned(ctx, scope, 14)
+	godebug.Line(ctx, scope, 15)
+	println(len(buf), total, done)
+}
+
+var mark_in_go_contents = `package main
+
+func main() {
+	var buf []int
+	total := 0
+	ratio := 1.0
+	done := false
+	_ = "breakpoint"
+	for i := 0; i < 5; i++ {
+		buf = append(buf, i)
+		total += i
+		ratio /= 2
+	}
+	done = true
+	println(len(buf), total, done)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -2,27 +2,25 @@ package main
 
 import "github.com/mailgun/godebug/lib"
 
-var method_in_go_scope = godebug.EnteringNewFile(main_pkg_scope, method_in_go_contents)
+var method_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/method-in.go", method_in_go_contents)
 
 type Foo int
 
-func (f Foo) Double() Foo {
-	var result1 Foo
+func (f Foo) Double() (result1 Foo) {
 	ctx, ok := godebug.EnterFunc(func() {
 		result1 = f.Double()
 	})
 	if !ok {
 		return result1
 	}
-	defer godebug.ExitFunc(ctx)
+	defer godebug.ExitFunc(ctx, &result1)
 	scope := method_in_go_scope.EnteringNewChildScope()
 	scope.Declare("f", &f)
 	godebug.Line(ctx, scope, 6)
 	return f * 2
 }
 
-func (Foo) Seven() Foo {
-	var result1 Foo
+func (Foo) Seven() (result1 Foo) {
 	var receiver Foo
 	ctx, ok := godebug.EnterFunc(func() {
 		result1 = receiver.Seven()
@@ -30,13 +28,12 @@ func (Foo) Seven() Foo {
 	if !ok {
 		return result1
 	}
-	defer godebug.ExitFunc(ctx)
+	defer godebug.ExitFunc(ctx, &result1)
 	godebug.Line(ctx, method_in_go_scope, 10)
 	return Foo(7)
 }
 
-func (_ Foo) Bar() int {
-	var result1 int
+func (_ Foo) Bar() (result1 int) {
 	var receiver Foo
 	ctx, ok := godebug.EnterFunc(func() {
 		result1 = receiver.Bar()
@@ -44,7 +41,7 @@ func (_ Foo) Bar() int {
 	if !ok {
 		return result1
 	}
-	defer godebug.ExitFunc(ctx)
+	defer godebug.ExitFunc(ctx, &result1)
 	godebug.Line(ctx, method_in_go_scope, 14)
 	return 0
 }
@@ -0,0 +1,26 @@
+package main
+
+type counter struct {
+	n int
+}
+
+func (c *counter) add(d int) int {
+	c.n += d
+	return c.n
+}
+
+func (c counter) get() int {
+	return c.n
+}
+
+func main() {
+	c := &counter{n: 1}
+	add := c.add
+	get := counter.get
+	addTo := (*counter).add
+	snap := c.get // binds a copy of *c now
+	_ = "breakpoint"
+	add(2)
+	addTo(c, 3)
+	println(get(*c), snap())
+}
@@ -0,0 +1,113 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var method_value_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "method-value-in.go", method_value_in_go_contents)
+
+type counter struct {
+	n int
+}
+
+func (c *counter) add(d int) (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = c.add(d)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := method_value_in_go_scope.EnteringNewChildScope()
+	scope.Declare("c", &c, "d", &d)
+	godebug.Line(ctx, scope, 8)
+	c.n += d
+	godebug.Assigned(ctx, scope, 8)
+	godebug.Line(ctx, scope, 9)
+	return c.n
+}
+
+func (c counter) get() (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = c.get()
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := method_value_in_go_scope.EnteringNewChildScope()
+	scope.Declare("c", &c)
+	godebug.Line(ctx, scope, 13)
+	return c.n
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, method_value_in_go_scope, 17)
+	c := &counter{n: 1}
+	scope := method_value_in_go_scope.EnteringNewChildScope()
+	scope.Declare("c", &c)
+	godebug.Line(ctx, scope, 18)
+	add := c.add
+	scope.Declare("add", &add)
+	godebug.Line(ctx, scope, 19)
+	get := counter.get
+	scope.Declare("get", &get)
+	godebug.Line(ctx, scope, 20)
+	addTo := (*counter).add
+	scope.Declare("addTo", &addTo)
+	godebug.Line(ctx, scope, 21)
+	snap := c.get
+	scope.Declare("snap", &snap)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 22)
+	godebug.Line(ctx, scope, 23)
+
+	add(2)
+	godebug.Line(ctx, scope, 24)
+	addTo(c, 3)
+	godebug.Line(ctx, scope, 25)
+	println(get(*c), snap())
+}
+
+var method_value_in_go_contents = `package main
+
+type counter struct {
+	n int
+}
+
+func (c *counter) add(d int) int {
+	c.n += d
+	return c.n
+}
+
+func (c counter) get() int {
+	return c.n
+}
+
+func main() {
+	c := &counter{n: 1}
+	add := c.add
+	get := counter.get
+	addTo := (*counter).add
+	snap := c.get // binds a copy of *c now
+	_ = "breakpoint"
+	add(2)
+	addTo(c, 3)
+	println(get(*c), snap())
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
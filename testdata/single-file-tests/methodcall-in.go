@@ -0,0 +1,43 @@
+package main
+
+type Buffer struct {
+	data []byte
+}
+
+func (b *Buffer) Write(s string) {
+	b.data = append(b.data, s...)
+}
+
+func (b *Buffer) Len() int {
+	return len(b.data)
+}
+
+func (b *Buffer) String() string {
+	return string(b.data)
+}
+
+func (b *Buffer) Reset() {
+	b.data = b.data[:0]
+}
+
+func (b *Buffer) Last() byte {
+	if len(b.data) == 0 {
+		panic("empty buffer")
+	}
+	return b.data[len(b.data)-1]
+}
+
+func (b *Buffer) cap() int {
+	return cap(b.data)
+}
+
+func main() {
+	b := &Buffer{}
+	b.Write("hello")
+	var s interface {
+		String() string
+	}
+	_ = "breakpoint"
+	_ = b.Len()
+	_ = s
+}
@@ -0,0 +1,181 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var methodcall_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/methodcall-in.go", methodcall_in_go_contents)
+
+type Buffer struct {
+	data []byte
+}
+
+func (b *Buffer) Write(s string) {
+	ctx, ok := godebug.EnterFunc(func() {
+		b.Write(s)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := methodcall_in_go_scope.EnteringNewChildScope()
+	scope.Declare("b", &b, "s", &s)
+	godebug.Line(ctx, scope, 8)
+	b.data = append(b.data, s...)
+	godebug.Assigned(ctx, scope, 8)
+}
+
+func (b *Buffer) Len() (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = b.Len()
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := methodcall_in_go_scope.EnteringNewChildScope()
+	scope.Declare("b", &b)
+	godebug.Line(ctx, scope, 12)
+	return len(b.data)
+}
+
+func (b *Buffer) String() (result1 string) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = b.String()
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := methodcall_in_go_scope.EnteringNewChildScope()
+	scope.Declare("b", &b)
+	godebug.Line(ctx, scope, 16)
+	return string(b.data)
+}
+
+func (b *Buffer) Reset() {
+	ctx, ok := godebug.EnterFunc(b.Reset)
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := methodcall_in_go_scope.EnteringNewChildScope()
+	scope.Declare("b", &b)
+	godebug.Line(ctx, scope, 20)
+	b.data = b.data[:0]
+	godebug.Assigned(ctx, scope, 20)
+}
+
+func (b *Buffer) Last() (result1 byte) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = b.Last()
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := methodcall_in_go_scope.EnteringNewChildScope()
+	scope.Declare("b", &b)
+	godebug.Line(ctx, scope, 24)
+	if len(b.data) == 0 {
+		godebug.Line(ctx, scope, 25)
+		panic("empty buffer")
+	}
+	godebug.Line(ctx, scope, 27)
+	return b.data[len(b.data)-1]
+}
+
+func (b *Buffer) cap() (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = b.cap()
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := methodcall_in_go_scope.EnteringNewChildScope()
+	scope.Declare("b", &b)
+	godebug.Line(ctx, scope, 31)
+	return cap(b.data)
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, methodcall_in_go_scope, 35)
+	b := &Buffer{}
+	scope := methodcall_in_go_scope.EnteringNewChildScope()
+	scope.Declare("b", &b)
+	godebug.Line(ctx, scope, 36)
+	b.Write("hello")
+	godebug.Line(ctx, scope, 37)
+	var s interface {
+		String() string
+	}
+	scope.Declare("s", &s)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 40)
+	godebug.Line(ctx, scope, 41)
+
+	_ = b.Len()
+	godebug.Line(ctx, scope, 42)
+	_ = s
+}
+
+var methodcall_in_go_contents = `package main
+
+type Buffer struct {
+	data []byte
+}
+
+func (b *Buffer) Write(s string) {
+	b.data = append(b.data, s...)
+}
+
+func (b *Buffer) Len() int {
+	return len(b.data)
+}
+
+func (b *Buffer) String() string {
+	return string(b.data)
+}
+
+func (b *Buffer) Reset() {
+	b.data = b.data[:0]
+}
+
+func (b *Buffer) Last() byte {
+	if len(b.data) == 0 {
+		panic("empty buffer")
+	}
+	return b.data[len(b.data)-1]
+}
+
+func (b *Buffer) cap() int {
+	return cap(b.data)
+}
+
+func main() {
+	b := &Buffer{}
+	b.Write("hello")
+	var s interface {
+		String() string
+	}
+	_ = "breakpoint"
+	_ = b.Len()
+	_ = s
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
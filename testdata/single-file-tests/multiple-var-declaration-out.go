@@ -5,7 +5,7 @@ import (
 	"github.com/mailgun/godebug/lib"
 )
 
-var multiple_var_declaration_in_go_scope = godebug.EnteringNewFile(main_pkg_scope, multiple_var_declaration_in_go_contents)
+var multiple_var_declaration_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/multiple-var-declaration-in.go", multiple_var_declaration_in_go_contents)
 
 func main() {
 	ctx, ok := godebug.EnterFunc(main)
@@ -25,10 +25,13 @@ func main() {
 			scope.Declare("x", &x, "y", &y, "err", &err)
 			godebug.Line(ctx, scope, 14)
 			x = 2
+			godebug.Assigned(ctx, scope, 14)
 			godebug.Line(ctx, scope, 15)
 			y = 3
+			godebug.Assigned(ctx, scope, 15)
 			godebug.Line(ctx, scope, 16)
 			err = nil
+			godebug.Assigned(ctx, scope, 16)
 			godebug.Line(ctx, scope, 17)
 			if err != nil {
 				godebug.Line(ctx, scope, 18)
@@ -5,13 +5,12 @@ import (
 	_godebug "github.com/mailgun/godebug/lib"
 )
 
-var name_conflicts_in_go_scope = _godebug.EnteringNewFile(main_pkg_scope, name_conflicts_in_go_contents)
+var name_conflicts_in_go_scope = _godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/name-conflicts-in.go", name_conflicts_in_go_contents)
 
 type Foo int
 
-func (Foo) DoStuff(int) int {
+func (Foo) DoStuff(int) (_result1 int) {
 	var _input1 int
-	var _result1 int
 	var _receiver Foo
 	_ctx, __ok := _godebug.EnterFunc(func() {
 		_result1 = _receiver.DoStuff(_input1)
@@ -19,7 +18,7 @@ func (Foo) DoStuff(int) int {
 	if !__ok {
 		return _result1
 	}
-	defer _godebug.ExitFunc(_ctx)
+	defer _godebug.ExitFunc(_ctx, &_result1)
 	_godebug.Line(_ctx, name_conflicts_in_go_scope, 8)
 	var fn, ok, _ok, ctx, result1, input1, receiver, name_conflicts_in_goScope, scope int
 	__scope := name_conflicts_in_go_scope.EnteringNewChildScope()
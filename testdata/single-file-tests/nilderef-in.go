@@ -0,0 +1,22 @@
+package main
+
+type T struct{ n int }
+
+func get(t *T) int {
+	x := 1
+	return t.n + x
+}
+
+func try(t *T) {
+	defer func() {
+		println("recovered:", recover() != nil)
+	}()
+	get(t)
+}
+
+func main() {
+	_ = "breakpoint"
+	try(&T{n: 1})
+	try(nil)
+	try(nil)
+}
@@ -0,0 +1,109 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var nilderef_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/nilderef-in.go", nilderef_in_go_contents)
+
+type T struct{ n int }
+
+func get(t *T) (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = get(t)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := nilderef_in_go_scope.EnteringNewChildScope()
+	scope.Declare("t", &t)
+	godebug.Line(ctx, scope, 6)
+	x := 1
+	scope.Declare("x", &x)
+	godebug.Line(ctx, scope, 7)
+	return t.n + x
+}
+
+func try(t *T) {
+	ctx, ok := godebug.EnterFunc(func() {
+		try(t)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := nilderef_in_go_scope.EnteringNewChildScope()
+	scope.Declare("t", &t)
+	godebug.Line(ctx, scope, 11)
+	defer func() {
+		r := make(chan chan interface {
+		})
+		recovers, panicChan := godebug.EnterFuncWithRecovers(r, func(ctx *godebug.Context) {
+			godebug.Line(ctx, scope, 12)
+			println("recovered:", <-(<-r) != nil)
+		})
+		for rr := range recovers {
+			rr <- recover()
+		}
+		if v, ok := <-panicChan; ok {
+			panic(v)
+		}
+	}()
+	defer godebug.Defer(ctx, scope, 11)
+	godebug.Line(ctx, scope, 14)
+	get(t)
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, nilderef_in_go_scope, 18)
+	godebug.Line(ctx, nilderef_in_go_scope, 19)
+
+	try(&T{n: 1})
+	godebug.Line(ctx, nilderef_in_go_scope, 20)
+	try(nil)
+	godebug.Line(ctx, nilderef_in_go_scope, 21)
+	try(nil)
+}
+
+var nilderef_in_go_contents = `package main
+
+type T struct{ n int }
+
+func get(t *T) int {
+	x := 1
+	return t.n + x
+}
+
+func try(t *T) {
+	defer func() {
+		println("recovered:", recover() != nil)
+	}()
+	get(t)
+}
+
+func main() {
+	_ = "breakpoint"
+	try(&T{n: 1})
+	try(nil)
+	try(nil)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"get": get,
+		"try": try,
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,15 @@
+package main
+
+type node struct {
+	next *node
+}
+
+func main() {
+	a, b := 3, 4
+	var x *node
+	s := []string{"a", "b"}
+	var err error
+	ok := a < b
+	_ = "breakpoint"
+	println(a, b, x, len(s), err, ok)
+}
@@ -0,0 +1,67 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var operators_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/operators-in.go", operators_in_go_contents)
+
+type node struct {
+	next *node
+}
+
+func main() {
+	ctx, _ok := godebug.EnterFunc(main)
+	if !_ok {
+		return
+	}
+	godebug.Line(ctx, operators_in_go_scope, 8)
+	a, b := 3, 4
+	scope := operators_in_go_scope.EnteringNewChildScope()
+	scope.Declare("a", &a, "b", &b)
+	godebug.Line(ctx, scope, 9)
+	var x *node
+	scope.Declare("x", &x)
+	godebug.Line(ctx, scope, 10)
+	s := []string{"a", "b"}
+	scope.Declare("s", &s)
+	godebug.Line(ctx, scope, 11)
+	var err error
+	scope.Declare("err", &err)
+	godebug.Line(ctx, scope, 12)
+	ok := a < b
+	scope.Declare("ok", &ok)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 13)
+	godebug.Line(ctx, scope, 14)
+
+	println(a, b, x, len(s), err, ok)
+}
+
+var operators_in_go_contents = `package main
+
+type node struct {
+	next *node
+}
+
+func main() {
+	a, b := 3, 4
+	var x *node
+	s := []string{"a", "b"}
+	var err error
+	ok := a < b
+	_ = "breakpoint"
+	println(a, b, x, len(s), err, ok)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
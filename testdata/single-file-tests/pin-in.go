@@ -0,0 +1,14 @@
+package main
+
+func main() {
+	total := 0
+	_ = "breakpoint"
+	for i := 1; i <= 3; i++ {
+		total += double(i)
+	}
+	println(total)
+}
+
+func double(n int) int {
+	return n * 2
+}
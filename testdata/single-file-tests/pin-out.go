@@ -0,0 +1,76 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var pin_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "pin-in.go", pin_in_go_contents)
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, pin_in_go_scope, 4)
+	total := 0
+	scope := pin_in_go_scope.EnteringNewChildScope()
+	scope.Declare("total", &total)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 5)
+	{
+		scope := scope.EnteringNewChildScope()
+
+		for i := 1; i <= 3; i++ {
+			godebug.Line(ctx, scope, 6)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 7)
+			total += double(i)
+			godebug.Assigned(ctx, scope, 7)
+		}
+		godebug.Line(ctx, scope, 6)
+	}
+	godebug.Line(ctx, scope, 9)
+	println(total)
+}
+
+func double(n int) (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = double(n)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := pin_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 13)
+	return n * 2
+}
+
+var pin_in_go_contents = `package main
+
+func main() {
+	total := 0
+	_ = "breakpoint"
+	for i := 1; i <= 3; i++ {
+		total += double(i)
+	}
+	println(total)
+}
+
+func double(n int) int {
+	return n * 2
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+		"double": double,
+	}
+}
\ No newline at end of file
@@ -5,7 +5,7 @@ import (
 	"github.com/mailgun/godebug/lib"
 )
 
-var recover_in_go_scope = godebug.EnteringNewFile(main_pkg_scope, recover_in_go_contents)
+var recover_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/recover-in.go", recover_in_go_contents)
 
 func r1() {
 	_r := make(chan chan interface {
@@ -0,0 +1,30 @@
+package main
+
+func fib(n int) int {
+	if n < 2 {
+		return n
+	}
+	return fib(n-1) + fib(n-2)
+}
+
+func isEven(n int) bool {
+	if n == 0 {
+		return true
+	}
+	return isOdd(n - 1)
+}
+
+func isOdd(n int) bool {
+	if n == 0 {
+		return false
+	}
+	return isEven(n - 1)
+}
+
+func main() {
+	_ = "breakpoint"
+	f := fib(3)
+	e := isEven(3)
+	t := func(n int) int { return fib(n) + fib(n) }(1)
+	println(f, e, t)
+}
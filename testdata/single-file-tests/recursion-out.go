@@ -0,0 +1,147 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var recursion_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/recursion-in.go", recursion_in_go_contents)
+
+func fib(n int) (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = fib(n)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := recursion_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 4)
+	if n < 2 {
+		godebug.Line(ctx, scope, 5)
+		return n
+	}
+	godebug.Line(ctx, scope, 7)
+	return fib(n-1) + fib(n-2)
+}
+
+func isEven(n int) (result1 bool) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = isEven(n)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := recursion_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 11)
+	if n == 0 {
+		godebug.Line(ctx, scope, 12)
+		return true
+	}
+	godebug.Line(ctx, scope, 14)
+	return isOdd(n - 1)
+}
+
+func isOdd(n int) (result1 bool) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = isOdd(n)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := recursion_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 18)
+	if n == 0 {
+		godebug.Line(ctx, scope, 19)
+		return false
+	}
+	godebug.Line(ctx, scope, 21)
+	return isEven(n - 1)
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, recursion_in_go_scope, 25)
+	godebug.Line(ctx, recursion_in_go_scope, 26)
+
+	f := fib(3)
+	scope := recursion_in_go_scope.EnteringNewChildScope()
+	scope.Declare("f", &f)
+	godebug.Line(ctx, scope, 27)
+	e := isEven(3)
+	scope.Declare("e", &e)
+	godebug.Line(ctx, scope, 28)
+	t := func(n int) int {
+		var result1 int
+		fn := func(ctx *godebug.Context) {
+			result1 = func() int {
+				scope := scope.EnteringNewChildScope()
+				scope.Declare("n", &n)
+				godebug.Line(ctx, scope, 28)
+				return fib(n) + fib(n)
+			}()
+		}
+		if ctx, ok := godebug.EnterFuncLit(fn); ok {
+			defer godebug.ExitFunc(ctx, &result1)
+			fn(ctx)
+		}
+		return result1
+	}(1)
+	scope.Declare("t", &t)
+	godebug.Line(ctx, scope, 29)
+	println(f, e, t)
+}
+
+var recursion_in_go_contents = `package main
+
+func fib(n int) int {
+	if n < 2 {
+		return n
+	}
+	return fib(n-1) + fib(n-2)
+}
+
+func isEven(n int) bool {
+	if n == 0 {
+		return true
+	}
+	return isOdd(n - 1)
+}
+
+func isOdd(n int) bool {
+	if n == 0 {
+		return false
+	}
+	return isEven(n - 1)
+}
+
+func main() {
+	_ = "breakpoint"
+	f := fib(3)
+	e := isEven(3)
+	t := func(n int) int { return fib(n) + fib(n) }(1)
+	println(f, e, t)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"fib": fib,
+		"isEven": isEven,
+		"isOdd": isOdd,
+		"main": main,
+	}
+}
\ No newline at end of file
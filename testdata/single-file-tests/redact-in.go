@@ -0,0 +1,18 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+type login struct {
+	User     string
+	Password string
+	token    []byte
+}
+
+func main() {
+	godebug.SetRedactor(godebug.RedactSecrets)
+	l := &login{User: "gopher", Password: "hunter2", token: []byte("abc")}
+	apiToken := "s3cr3t"
+	other := login{User: "gopher", Password: "swordfish"}
+	_ = "breakpoint"
+	_, _, _ = l, apiToken, other
+}
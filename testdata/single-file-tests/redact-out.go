@@ -0,0 +1,68 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var redact_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/redact-in.go", redact_in_go_contents)
+
+type login struct {
+	User     string
+	Password string
+	token    []byte
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, redact_in_go_scope, 12)
+	godebug.SetRedactor(godebug.RedactSecrets)
+	godebug.Line(ctx, redact_in_go_scope, 13)
+	l := &login{User: "gopher", Password: "hunter2", token: []byte("abc")}
+	scope := redact_in_go_scope.EnteringNewChildScope()
+	scope.Declare("l", &l)
+	godebug.Line(ctx, scope, 14)
+	apiToken := "s3cr3t"
+	scope.Declare("apiToken", &apiToken)
+	godebug.Line(ctx, scope, 15)
+	other := login{User: "gopher", Password: "swordfish"}
+	scope.Declare("other", &other)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 16)
+	godebug.Line(ctx, scope, 17)
+
+	_, _, _ = l, apiToken, other
+}
+
+var redact_in_go_contents = `package main
+
+import "github.com/mailgun/godebug/lib"
+
+type login struct {
+	User     string
+	Password string
+	token    []byte
+}
+
+func main() {
+	godebug.SetRedactor(godebug.RedactSecrets)
+	l := &login{User: "gopher", Password: "hunter2", token: []byte("abc")}
+	apiToken := "s3cr3t"
+	other := login{User: "gopher", Password: "swordfish"}
+	_ = "breakpoint"
+	_, _, _ = l, apiToken, other
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -2,7 +2,7 @@ package main
 
 import "github.com/mailgun/godebug/lib"
 
-var regression_in_go_scope = godebug.EnteringNewFile(main_pkg_scope, regression_in_go_contents)
+var regression_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/regression-in.go", regression_in_go_contents)
 
 func main() {
 	ctx, _ok := godebug.EnterFunc(main)
@@ -22,7 +22,7 @@ func main() {
 			}()
 		}
 		if ctx, _ok := godebug.EnterFuncLit(fn); _ok {
-			defer godebug.ExitFunc(ctx)
+			defer godebug.ExitFunc(ctx, &result1)
 			fn(ctx)
 		}
 		return result1
@@ -108,15 +108,14 @@ func main() {
 	T{}.name3()
 }
 
-func _switch() int {
-	var result1 int
+func _switch() (result1 int) {
 	ctx, _ok := godebug.EnterFunc(func() {
 		result1 = _switch()
 	})
 	if !_ok {
 		return result1
 	}
-	defer godebug.ExitFunc(ctx)
+	defer godebug.ExitFunc(ctx, &result1)
 	godebug.Line(ctx, regression_in_go_scope, 51)
 
 	switch {
@@ -132,15 +131,14 @@ func _switch() int {
 	}
 }
 
-func _select() int {
-	var result1 int
+func _select() (result1 int) {
 	ctx, _ok := godebug.EnterFunc(func() {
 		result1 = _select()
 	})
 	if !_ok {
 		return result1
 	}
-	defer godebug.ExitFunc(ctx)
+	defer godebug.ExitFunc(ctx, &result1)
 	godebug.Select(ctx, regression_in_go_scope, 61)
 
 	select {
@@ -183,13 +181,14 @@ func name2() (_name2 string) {
 	if !_ok {
 		return _name2
 	}
-	defer godebug.ExitFunc(ctx)
+	defer godebug.ExitFunc(ctx, &_name2)
 	scope := regression_in_go_scope.EnteringNewChildScope()
 	scope.Declare("name2", &_name2)
 	godebug.Line(ctx, scope, 78)
 	if true {
 		godebug.Line(ctx, scope, 79)
 		_name2 = "foo"
+		godebug.Assigned(ctx, scope, 79)
 	}
 	godebug.Line(ctx, scope, 81)
 	return _name2
@@ -262,6 +261,7 @@ func doFallthrough() {
 	case false:
 		godebug.Line(ctx, scope, 115)
 		fellthrough = true
+		godebug.Assigned(ctx, scope, 115)
 	}
 	godebug.Line(ctx, scope, 117)
 	if !fellthrough {
@@ -270,15 +270,14 @@ func doFallthrough() {
 	}
 }
 
-func a() int {
-	var result1 int
+func a() (result1 int) {
 	ctx, _ok := godebug.EnterFunc(func() {
 		result1 = a()
 	})
 	if !_ok {
 		return result1
 	}
-	defer godebug.ExitFunc(ctx)
+	defer godebug.ExitFunc(ctx, &result1)
 	godebug.Line(ctx, regression_in_go_scope, 123)
 	return 0
 }
@@ -334,6 +333,7 @@ func unexportedField() {
 	scope.Declare("f", &f)
 	godebug.Line(ctx, scope, 143)
 	f.bar = 5
+	godebug.Assigned(ctx, scope, 143)
 	godebug.SetTraceGen(ctx)
 	godebug.Line(ctx, scope, 144)
 
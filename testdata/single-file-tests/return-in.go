@@ -0,0 +1,22 @@
+package main
+
+type T struct{ n int }
+
+func (t *T) Inc() int {
+	t.n++
+	return t.n
+}
+
+func add(a, b int) (sum int) {
+	sum = a + b
+	return sum
+}
+
+func main() {
+	_ = "breakpoint"
+	x := add(1, 2)
+	y := add(x, 3)
+	t := &T{}
+	t.Inc()
+	_ = y
+}
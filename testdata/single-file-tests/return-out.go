@@ -0,0 +1,103 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var return_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/return-in.go", return_in_go_contents)
+
+type T struct{ n int }
+
+func (t *T) Inc() (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = t.Inc()
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := return_in_go_scope.EnteringNewChildScope()
+	scope.Declare("t", &t)
+	godebug.Line(ctx, scope, 6)
+	t.n++
+	godebug.Assigned(ctx, scope, 6)
+	godebug.Line(ctx, scope, 7)
+	return t.n
+}
+
+func add(a, b int) (sum int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		sum = add(a, b)
+	})
+	if !ok {
+		return sum
+	}
+	defer godebug.ExitFunc(ctx, &sum)
+	scope := return_in_go_scope.EnteringNewChildScope()
+	scope.Declare("a", &a, "b", &b, "sum", &sum)
+	godebug.Line(ctx, scope, 11)
+	sum = a + b
+	godebug.Assigned(ctx, scope, 11)
+	godebug.Line(ctx, scope, 12)
+	return sum
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, return_in_go_scope, 16)
+	godebug.Line(ctx, return_in_go_scope, 17)
+
+	x := add(1, 2)
+	scope := return_in_go_scope.EnteringNewChildScope()
+	scope.Declare("x", &x)
+	godebug.Line(ctx, scope, 18)
+	y := add(x, 3)
+	scope.Declare("y", &y)
+	godebug.Line(ctx, scope, 19)
+	t := &T{}
+	scope.Declare("t", &t)
+	godebug.Line(ctx, scope, 20)
+	t.Inc()
+	godebug.Line(ctx, scope, 21)
+	_ = y
+}
+
+var return_in_go_contents = `package main
+
+type T struct{ n int }
+
+func (t *T) Inc() int {
+	t.n++
+	return t.n
+}
+
+func add(a, b int) (sum int) {
+	sum = a + b
+	return sum
+}
+
+func main() {
+	_ = "breakpoint"
+	x := add(1, 2)
+	y := add(x, 3)
+	t := &T{}
+	t.Inc()
+	_ = y
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"add": add,
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,12 @@
+package main
+
+func main() {
+	total, count := 0, 0
+	_ = "breakpoint"
+	for i := 1; i <= 3; i++ {
+		total += i
+	}
+	count = 3
+	label := "done"
+	println(total, count, label)
+}
@@ -0,0 +1,65 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var scopediff_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "scopediff-in.go", scopediff_in_go_contents)
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, scopediff_in_go_scope, 4)
+	total, count := 0, 0
+	scope := scopediff_in_go_scope.EnteringNewChildScope()
+	scope.Declare("total", &total, "count", &count)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 5)
+	{
+		scope := scope.EnteringNewChildScope()
+
+		for i := 1; i <= 3; i++ {
+			godebug.Line(ctx, scope, 6)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 7)
+			total += i
+			godebug.Assigned(ctx, scope, 7)
+		}
+		godebug.Line(ctx, scope, 6)
+	}
+	godebug.Line(ctx, scope, 9)
+	count = 3
+	godebug.Assigned(ctx, scope, 9)
+	godebug.Line(ctx, scope, 10)
+	label := "done"
+	scope.Declare("label", &label)
+	godebug.Line(ctx, scope, 11)
+	println(total, count, label)
+}
+
+var scopediff_in_go_contents = `package main
+
+func main() {
+	total, count := 0, 0
+	_ = "breakpoint"
+	for i := 1; i <= 3; i++ {
+		total += i
+	}
+	count = 3
+	label := "done"
+	println(total, count, label)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
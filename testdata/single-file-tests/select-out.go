@@ -5,30 +5,28 @@ import (
 	"github.com/mailgun/godebug/lib"
 )
 
-var select_in_go_scope = godebug.EnteringNewFile(main_pkg_scope, select_in_go_contents)
+var select_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/select-in.go", select_in_go_contents)
 
-func foo() chan int {
-	var result1 chan int
+func foo() (result1 chan int) {
 	ctx, _ok := godebug.EnterFunc(func() {
 		result1 = foo()
 	})
 	if !_ok {
 		return result1
 	}
-	defer godebug.ExitFunc(ctx)
+	defer godebug.ExitFunc(ctx, &result1)
 	godebug.Line(ctx, select_in_go_scope, 6)
 	return make(chan int)
 }
 
-func bar() int {
-	var result1 int
+func bar() (result1 int) {
 	ctx, _ok := godebug.EnterFunc(func() {
 		result1 = bar()
 	})
 	if !_ok {
 		return result1
 	}
-	defer godebug.ExitFunc(ctx)
+	defer godebug.ExitFunc(ctx, &result1)
 	godebug.Line(ctx, select_in_go_scope, 10)
 	return 0
 }
@@ -49,6 +47,7 @@ func main() {
 			scope.Declare("i", &i)
 			godebug.Line(ctx, scope, 16)
 			c[i] = make(chan int, 1)
+			godebug.Assigned(ctx, scope, 16)
 		}
 		godebug.Line(ctx, scope, 15)
 	}
@@ -299,6 +298,7 @@ func main() {
 	godebug.Line(ctx, scope, 119)
 
 	c[0], c[1] = make(chan int), make(chan int)
+	godebug.Assigned(ctx, scope, 119)
 	godebug.Line(ctx, scope, 121)
 
 	go func() {
@@ -0,0 +1,25 @@
+package main
+
+type address struct {
+	City string
+	zip  string
+}
+
+type person struct {
+	Name string
+}
+
+type user struct {
+	*person
+	Address *address
+	home    address
+	Boss    *user
+}
+
+func main() {
+	u := &user{person: &person{"ann"}, Address: &address{City: "Austin", zip: "78701"}}
+	u.home = *u.Address
+	v := user{}
+	_ = "breakpoint"
+	println(u.Name, v.Address == nil)
+}
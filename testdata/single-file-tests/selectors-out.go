@@ -0,0 +1,83 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var selectors_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/selectors-in.go", selectors_in_go_contents)
+
+type address struct {
+	City string
+	zip  string
+}
+
+type person struct {
+	Name string
+}
+
+type user struct {
+	*person
+	Address *address
+	home    address
+	Boss    *user
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, selectors_in_go_scope, 20)
+	u := &user{person: &person{"ann"}, Address: &address{City: "Austin", zip: "78701"}}
+	scope := selectors_in_go_scope.EnteringNewChildScope()
+	scope.Declare("u", &u)
+	godebug.Line(ctx, scope, 21)
+	u.home = *u.Address
+	godebug.Assigned(ctx, scope, 21)
+	godebug.Line(ctx, scope, 22)
+	v := user{}
+	scope.Declare("v", &v)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 23)
+	godebug.Line(ctx, scope, 24)
+
+	println(u.Name, v.Address == nil)
+}
+
+var selectors_in_go_contents = `package main
+
+type address struct {
+	City string
+	zip  string
+}
+
+type person struct {
+	Name string
+}
+
+type user struct {
+	*person
+	Address *address
+	home    address
+	Boss    *user
+}
+
+func main() {
+	u := &user{person: &person{"ann"}, Address: &address{City: "Austin", zip: "78701"}}
+	u.home = *u.Address
+	v := user{}
+	_ = "breakpoint"
+	println(u.Name, v.Address == nil)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,27 @@
+package main
+
+type job struct {
+	id   int
+	name string
+}
+
+func next(n int) int {
+	return n + 1
+}
+
+func main() {
+	jobs := make(chan job, 4)
+	ids := make(chan int, 4)
+	j := job{id: 1, name: "first"}
+	_ = "breakpoint"
+	jobs <- j
+	ids <- j.id
+	ids <- 7
+	ids <- next(j.id)
+	ids <- <-ids
+	select {
+	case ids <- j.id * 10:
+	default:
+	}
+	println(len(jobs), len(ids))
+}
@@ -0,0 +1,110 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var send_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "send-in.go", send_in_go_contents)
+
+type job struct {
+	id   int
+	name string
+}
+
+func next(n int) (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = next(n)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := send_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 9)
+	return n + 1
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, send_in_go_scope, 13)
+	jobs := make(chan job, 4)
+	scope := send_in_go_scope.EnteringNewChildScope()
+	scope.Declare("jobs", &jobs)
+	godebug.Line(ctx, scope, 14)
+	ids := make(chan int, 4)
+	scope.Declare("ids", &ids)
+	godebug.Line(ctx, scope, 15)
+	j := job{id: 1, name: "first"}
+	scope.Declare("j", &j)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 16)
+	godebug.Line(ctx, scope, 17)
+
+	jobs <- j
+	godebug.Line(ctx, scope, 18)
+	ids <- j.id
+	godebug.Line(ctx, scope, 19)
+	ids <- 7
+	godebug.Line(ctx, scope, 20)
+	ids <- next(j.id)
+	godebug.Line(ctx, scope, 21)
+	ids <- <-ids
+	godebug.Select(ctx, scope, 22)
+	select {
+	case <-godebug.Comm(ctx, scope, 23):
+		panic("impossible")
+	case ids <- j.id * 10:
+		godebug.Line(ctx, scope, 23)
+	default:
+		godebug.Line(ctx, scope, 24)
+	case <-godebug.EndSelect(ctx, scope):
+		panic("impossible")
+	}
+	godebug.Line(ctx, scope, 26)
+	println(len(jobs), len(ids))
+}
+
+var send_in_go_contents = `package main
+
+type job struct {
+	id   int
+	name string
+}
+
+func next(n int) int {
+	return n + 1
+}
+
+func main() {
+	jobs := make(chan job, 4)
+	ids := make(chan int, 4)
+	j := job{id: 1, name: "first"}
+	_ = "breakpoint"
+	jobs <- j
+	ids <- j.id
+	ids <- 7
+	ids <- next(j.id)
+	ids <- <-ids
+	select {
+	case ids <- j.id * 10:
+	default:
+	}
+	println(len(jobs), len(ids))
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"next": next,
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,16 @@
+package main
+
+func count(n *int) int {
+	*n++
+	return *n
+}
+
+func main() {
+	n := 0
+	_ = "breakpoint"
+	count(&n)
+	x := count(&n)
+	println(n, x)
+	x = count(&n)
+	println(n, x)
+}
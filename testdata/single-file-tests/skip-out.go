@@ -0,0 +1,80 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var skip_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/skip-in.go", skip_in_go_contents)
+
+func count(n *int) (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = count(n)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := skip_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 4)
+	*n++
+	godebug.Assigned(ctx, scope, 4)
+	godebug.Line(ctx, scope, 5)
+	return *n
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, skip_in_go_scope, 9)
+	n := 0
+	scope := skip_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 10)
+	godebug.Line(ctx, scope, 11)
+
+	count(&n)
+	godebug.Line(ctx, scope, 12)
+	x := count(&n)
+	scope.Declare("x", &x)
+	godebug.Line(ctx, scope, 13)
+	println(n, x)
+	godebug.Line(ctx, scope, 14)
+	x = count(&n)
+	godebug.Assigned(ctx, scope, 14)
+	godebug.Line(ctx, scope, 15)
+	println(n, x)
+}
+
+var skip_in_go_contents = `package main
+
+func count(n *int) int {
+	*n++
+	return *n
+}
+
+func main() {
+	n := 0
+	_ = "breakpoint"
+	count(&n)
+	x := count(&n)
+	println(n, x)
+	x = count(&n)
+	println(n, x)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"count": count,
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,27 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+type noSource struct{}
+
+func (noSource) Error() string { return "no source" }
+
+func provide(file string) ([]string, error) {
+	lines := make([]string, 26)
+	for i := range lines {
+		lines[i] = "// provided line " + string(rune('A'+i))
+	}
+	return lines, nil
+}
+
+func broken(file string) ([]string, error) {
+	return nil, noSource{}
+}
+
+func main() {
+	_ = "breakpoint"
+	godebug.SetSourceProvider(provide)
+	_ = "provided"
+	godebug.SetSourceProvider(broken)
+	_ = "embedded again"
+}
@@ -0,0 +1,124 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var source_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/source-in.go", source_in_go_contents)
+
+type noSource struct{}
+
+func (noSource) Error() (result1 string) {
+	var receiver noSource
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = receiver.Error()
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	godebug.Line(ctx, source_in_go_scope, 7)
+	return "no source"
+}
+
+func provide(file string) (result1 []string, result2 error) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1, result2 = provide(file)
+	})
+	if !ok {
+		return result1, result2
+	}
+	defer godebug.ExitFunc(ctx, &result1, &result2)
+	scope := source_in_go_scope.EnteringNewChildScope()
+	scope.Declare("file", &file)
+	godebug.Line(ctx, scope, 10)
+	lines := make([]string, 26)
+	scope.Declare("lines", &lines)
+	{
+		scope := scope.EnteringNewChildScope()
+		for i := range lines {
+			godebug.Line(ctx, scope, 11)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 12)
+			lines[i] = "// provided line " + string(rune('A'+i))
+			godebug.Assigned(ctx, scope, 12)
+		}
+		godebug.Line(ctx, scope, 11)
+	}
+	godebug.Line(ctx, scope, 14)
+	return lines, nil
+}
+
+func broken(file string) (result1 []string, result2 error) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1, result2 = broken(file)
+	})
+	if !ok {
+		return result1, result2
+	}
+	defer godebug.ExitFunc(ctx, &result1, &result2)
+	scope := source_in_go_scope.EnteringNewChildScope()
+	scope.Declare("file", &file)
+	godebug.Line(ctx, scope, 18)
+	return nil, noSource{}
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, source_in_go_scope, 22)
+	godebug.Line(ctx, source_in_go_scope, 23)
+
+	godebug.SetSourceProvider(provide)
+	godebug.Line(ctx, source_in_go_scope, 24)
+	_ = "provided"
+	godebug.Line(ctx, source_in_go_scope, 25)
+	godebug.SetSourceProvider(broken)
+	godebug.Line(ctx, source_in_go_scope, 26)
+	_ = "embedded again"
+}
+
+var source_in_go_contents = `package main
+
+import "github.com/mailgun/godebug/lib"
+
+type noSource struct{}
+
+func (noSource) Error() string { return "no source" }
+
+func provide(file string) ([]string, error) {
+	lines := make([]string, 26)
+	for i := range lines {
+		lines[i] = "// provided line " + string(rune('A'+i))
+	}
+	return lines, nil
+}
+
+func broken(file string) ([]string, error) {
+	return nil, noSource{}
+}
+
+func main() {
+	_ = "breakpoint"
+	godebug.SetSourceProvider(provide)
+	_ = "provided"
+	godebug.SetSourceProvider(broken)
+	_ = "embedded again"
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"provide": provide,
+		"broken": broken,
+		"main": main,
+	}
+}
\ No newline at end of file
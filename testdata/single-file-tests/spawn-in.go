@@ -0,0 +1,15 @@
+package main
+
+func main() {
+	done := make(chan int)
+	_ = "breakpoint"
+	go func() {
+		done <- 1
+	}()
+	<-done
+	go func() {
+		n := 2
+		done <- n
+	}()
+	<-done
+}
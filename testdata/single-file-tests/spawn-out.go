@@ -0,0 +1,79 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var spawn_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/spawn-in.go", spawn_in_go_contents)
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, spawn_in_go_scope, 4)
+	done := make(chan int)
+	scope := spawn_in_go_scope.EnteringNewChildScope()
+	scope.Declare("done", &done)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 5)
+	godebug.Line(ctx, scope, 6)
+
+	go func() {
+		fn := func(ctx *godebug.Context) {
+			godebug.Line(ctx, scope, 7)
+			done <- 1
+		}
+		if ctx, ok := godebug.EnterFuncLit(fn); ok {
+			defer godebug.ExitFunc(ctx)
+			fn(ctx)
+		}
+	}()
+	godebug.Line(ctx, scope, 9)
+	<-done
+	godebug.Line(ctx, scope, 10)
+	go func() {
+		fn := func(ctx *godebug.Context) {
+			godebug.Line(ctx, scope, 11)
+			n := 2
+			scope := scope.EnteringNewChildScope()
+			scope.Declare("n", &n)
+			godebug.Line(ctx, scope, 12)
+			done <- n
+		}
+		if ctx, ok := godebug.EnterFuncLit(fn); ok {
+			defer godebug.ExitFunc(ctx)
+			fn(ctx)
+		}
+	}()
+	godebug.Line(ctx, scope, 14)
+	<-done
+}
+
+var spawn_in_go_contents = `package main
+
+func main() {
+	done := make(chan int)
+	_ = "breakpoint"
+	go func() {
+		done <- 1
+	}()
+	<-done
+	go func() {
+		n := 2
+		done <- n
+	}()
+	<-done
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
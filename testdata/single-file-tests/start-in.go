@@ -0,0 +1,13 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+func init() {
+	godebug.SetBreakAtStart(true)
+}
+
+func main() {
+	x := 1
+	x++
+	println(x)
+}
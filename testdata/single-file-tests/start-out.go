@@ -0,0 +1,53 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var start_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/start-in.go", start_in_go_contents)
+
+func init() {
+	godebug.SetBreakAtStart(true)
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, start_in_go_scope, 10)
+	x := 1
+	scope := start_in_go_scope.EnteringNewChildScope()
+	scope.Declare("x", &x)
+	godebug.Line(ctx, scope, 11)
+	x++
+	godebug.Assigned(ctx, scope, 11)
+	godebug.Line(ctx, scope, 12)
+	println(x)
+}
+
+var start_in_go_contents = `package main
+
+import "github.com/mailgun/godebug/lib"
+
+func init() {
+	godebug.SetBreakAtStart(true)
+}
+
+func main() {
+	x := 1
+	x++
+	println(x)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,15 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+func report() {
+	println(godebug.IsActive(), godebug.State().String())
+}
+
+func main() {
+	report()
+	_ = "breakpoint"
+	report()
+	report()
+	report()
+}
@@ -0,0 +1,64 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var state_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/state-in.go", state_in_go_contents)
+
+func report() {
+	ctx, ok := godebug.EnterFunc(report)
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	godebug.Line(ctx, state_in_go_scope, 6)
+	println(godebug.IsActive(), godebug.State().String())
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, state_in_go_scope, 10)
+	report()
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, state_in_go_scope, 11)
+	godebug.Line(ctx, state_in_go_scope, 12)
+
+	report()
+	godebug.Line(ctx, state_in_go_scope, 13)
+	report()
+	godebug.Line(ctx, state_in_go_scope, 14)
+	report()
+}
+
+var state_in_go_contents = `package main
+
+import "github.com/mailgun/godebug/lib"
+
+func report() {
+	println(godebug.IsActive(), godebug.State().String())
+}
+
+func main() {
+	report()
+	_ = "breakpoint"
+	report()
+	report()
+	report()
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"report": report,
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,16 @@
+package main
+
+func double(n int) int { return 2 * n }
+
+func main() {
+	n := 0
+	for j := 0; j < 2; j++ {
+		_ = "breakpoint"
+		for i := 0; i < 3; i++ { n += i }
+		if n == 0 {
+		} else if m := double(n); m > 4 {
+			n = m
+		}
+	}
+	println(n)
+}
@@ -0,0 +1,98 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var step_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/step-in.go", step_in_go_contents)
+
+func double(n int) (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = double(n)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := step_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 3)
+	return 2 * n
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, step_in_go_scope, 6)
+	n := 0
+	scope := step_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	{
+		scope := scope.EnteringNewChildScope()
+		for j := 0; j < 2; j++ {
+			godebug.Line(ctx, scope, 7)
+			scope.Declare("j", &j)
+			godebug.SetTraceGen(ctx)
+			godebug.Line(ctx, scope, 8)
+			{
+				scope := scope.EnteringNewChildScope()
+
+				for i := 0; i < 3; i++ {
+					godebug.Line(ctx, scope, 9)
+					scope.Declare("i", &i)
+					godebug.Line(ctx, scope, 9)
+					n += i
+					godebug.Assigned(ctx, scope, 9)
+				}
+				godebug.Line(ctx, scope, 9)
+			}
+			godebug.Line(ctx, scope, 10)
+			if n == 0 {
+			} else {
+				godebug.ElseIfSimpleStmt(ctx, scope, 11)
+				m := double(n)
+				godebug.ElseIfExpr(ctx, scope, 11)
+				if m > 4 {
+					godebug.Line(ctx, scope, 12)
+					n = m
+					godebug.Assigned(ctx, scope, 12)
+				}
+			}
+		}
+		godebug.Line(ctx, scope, 7)
+	}
+	godebug.Line(ctx, scope, 15)
+	println(n)
+}
+
+var step_in_go_contents = `package main
+
+func double(n int) int { return 2 * n }
+
+func main() {
+	n := 0
+	for j := 0; j < 2; j++ {
+		_ = "breakpoint"
+		for i := 0; i < 3; i++ { n += i }
+		if n == 0 {
+		} else if m := double(n); m > 4 {
+			n = m
+		}
+	}
+	println(n)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"double": double,
+		"main": main,
+	}
+}
\ No newline at end of file
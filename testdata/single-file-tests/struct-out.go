@@ -2,7 +2,7 @@ package main
 
 import "github.com/mailgun/godebug/lib"
 
-var struct_in_go_scope = godebug.EnteringNewFile(main_pkg_scope, struct_in_go_contents)
+var struct_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/struct-in.go", struct_in_go_contents)
 
 func main() {
 	ctx, ok := godebug.EnterFunc(main)
@@ -5,17 +5,16 @@ import (
 	"github.com/mailgun/godebug/lib"
 )
 
-var switch_in_go_scope = godebug.EnteringNewFile(main_pkg_scope, switch_in_go_contents)
+var switch_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/switch-in.go", switch_in_go_contents)
 
-func foo() interface{} {
-	var result1 interface{}
+func foo() (result1 interface{}) {
 	ctx, ok := godebug.EnterFunc(func() {
 		result1 = foo()
 	})
 	if !ok {
 		return result1
 	}
-	defer godebug.ExitFunc(ctx)
+	defer godebug.ExitFunc(ctx, &result1)
 	godebug.Line(ctx, switch_in_go_scope, 6)
 	return "hi"
 }
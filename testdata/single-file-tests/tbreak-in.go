@@ -0,0 +1,13 @@
+package main
+
+func main() {
+	_ = "breakpoint"
+	for i := 0; i < 4; i++ {
+		visit(i)
+	}
+	println("done")
+}
+
+func visit(i int) {
+	println("visit", i)
+}
@@ -0,0 +1,70 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var tbreak_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "tbreak-in.go", tbreak_in_go_contents)
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, tbreak_in_go_scope, 4)
+	{
+		scope := tbreak_in_go_scope.EnteringNewChildScope()
+
+		for i := 0; i < 4; i++ {
+			godebug.Line(ctx, scope, 5)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 6)
+			visit(i)
+		}
+		godebug.Line(ctx, scope, 5)
+	}
+	godebug.Line(ctx, tbreak_in_go_scope, 8)
+	println("done")
+}
+
+func visit(i int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		visit(i)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := tbreak_in_go_scope.EnteringNewChildScope()
+	scope.Declare("i", &i)
+	godebug.Line(ctx, scope, 12)
+	println("visit", i)
+}
+
+var tbreak_in_go_contents = `package main
+
+func main() {
+	_ = "breakpoint"
+	for i := 0; i < 4; i++ {
+		visit(i)
+	}
+	println("done")
+}
+
+func visit(i int) {
+	println("visit", i)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+		"visit": visit,
+	}
+}
\ No newline at end of file
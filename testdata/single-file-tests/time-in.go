@@ -0,0 +1,11 @@
+package main
+
+import "time"
+
+func main() {
+	d := 1500 * time.Millisecond
+	t := time.Date(2015, time.June, 3, 10, 30, 0, 0, time.UTC)
+	n := 42
+	_ = "breakpoint"
+	_, _, _ = d, t, n
+}
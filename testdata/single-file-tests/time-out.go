@@ -0,0 +1,56 @@
+package main
+
+import (
+	"time"
+	"github.com/mailgun/godebug/lib"
+)
+
+var time_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/time-in.go", time_in_go_contents)
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, time_in_go_scope, 6)
+	d := 1500 * time.Millisecond
+	scope := time_in_go_scope.EnteringNewChildScope()
+	scope.Declare("d", &d)
+	godebug.Line(ctx, scope, 7)
+	t := time.Date(2015, time.June, 3, 10, 30, 0, 0, time.UTC)
+	scope.Declare("t", &t)
+	godebug.Line(ctx, scope, 8)
+	n := 42
+	scope.Declare("n", &n)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 9)
+	godebug.Line(ctx, scope, 10)
+
+	_, _, _ = d, t, n
+}
+
+var time_in_go_contents = `package main
+
+import "time"
+
+func main() {
+	d := 1500 * time.Millisecond
+	t := time.Date(2015, time.June, 3, 10, 30, 0, 0, time.UTC)
+	n := 42
+	_ = "breakpoint"
+	_, _, _ = d, t, n
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+	}
+}
\ No newline at end of file
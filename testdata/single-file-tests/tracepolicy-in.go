@@ -0,0 +1,19 @@
+package main
+
+func worker(reached, resume, finished chan bool) {
+	_ = "breakpoint"
+	reached <- true
+	<-resume
+	println("in worker")
+	finished <- true
+}
+
+func main() {
+	reached, resume, finished := make(chan bool), make(chan bool), make(chan bool)
+	_ = "breakpoint"
+	go worker(reached, resume, finished)
+	<-reached
+	resume <- true
+	<-finished
+	println("done")
+}
@@ -0,0 +1,87 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var tracepolicy_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "tracepolicy-in.go", tracepolicy_in_go_contents)
+
+func worker(reached, resume, finished chan bool) {
+	ctx, ok := godebug.EnterFunc(func() {
+		worker(reached, resume, finished)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := tracepolicy_in_go_scope.EnteringNewChildScope()
+	scope.Declare("reached", &reached, "resume", &resume, "finished", &finished)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 4)
+	godebug.Line(ctx, scope, 5)
+
+	reached <- true
+	godebug.Line(ctx, scope, 6)
+	<-resume
+	godebug.Line(ctx, scope, 7)
+	println("in worker")
+	godebug.Line(ctx, scope, 8)
+	finished <- true
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, tracepolicy_in_go_scope, 12)
+	reached, resume, finished := make(chan bool), make(chan bool), make(chan bool)
+	scope := tracepolicy_in_go_scope.EnteringNewChildScope()
+	scope.Declare("reached", &reached, "resume", &resume, "finished", &finished)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 13)
+	godebug.Line(ctx, scope, 14)
+
+	go worker(reached, resume, finished)
+	godebug.Line(ctx, scope, 15)
+	<-reached
+	godebug.Line(ctx, scope, 16)
+	resume <- true
+	godebug.Line(ctx, scope, 17)
+	<-finished
+	godebug.Line(ctx, scope, 18)
+	println("done")
+}
+
+var tracepolicy_in_go_contents = `package main
+
+func worker(reached, resume, finished chan bool) {
+	_ = "breakpoint"
+	reached <- true
+	<-resume
+	println("in worker")
+	finished <- true
+}
+
+func main() {
+	reached, resume, finished := make(chan bool), make(chan bool), make(chan bool)
+	_ = "breakpoint"
+	go worker(reached, resume, finished)
+	<-reached
+	resume <- true
+	<-finished
+	println("done")
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"worker": worker,
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,31 @@
+package main
+
+type myErr struct{ msg string }
+
+func (e *myErr) Error() string { return e.msg }
+
+type holder struct {
+	err error
+	ptr *myErr
+}
+
+func find(fail bool) *myErr {
+	if fail {
+		return &myErr{"failed"}
+	}
+	return nil
+}
+
+func check() error {
+	return find(false)
+}
+
+func main() {
+	var none error
+	typed := check()
+	var ptr *myErr
+	h := holder{err: typed}
+	var any interface{} = ptr
+	_ = "breakpoint"
+	println(none == nil, typed == nil, ptr == nil, h.err == nil, any == nil)
+}
@@ -0,0 +1,133 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var typednil_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/typednil-in.go", typednil_in_go_contents)
+
+type myErr struct{ msg string }
+
+func (e *myErr) Error() (result1 string) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = e.Error()
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := typednil_in_go_scope.EnteringNewChildScope()
+	scope.Declare("e", &e)
+	godebug.Line(ctx, scope, 5)
+	return e.msg
+}
+
+type holder struct {
+	err error
+	ptr *myErr
+}
+
+func find(fail bool) (result1 *myErr) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = find(fail)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := typednil_in_go_scope.EnteringNewChildScope()
+	scope.Declare("fail", &fail)
+	godebug.Line(ctx, scope, 13)
+	if fail {
+		godebug.Line(ctx, scope, 14)
+		return &myErr{"failed"}
+	}
+	godebug.Line(ctx, scope, 16)
+	return nil
+}
+
+func check() (result1 error) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = check()
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	godebug.Line(ctx, typednil_in_go_scope, 20)
+	return find(false)
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, typednil_in_go_scope, 24)
+	var none error
+	scope := typednil_in_go_scope.EnteringNewChildScope()
+	scope.Declare("none", &none)
+	godebug.Line(ctx, scope, 25)
+	typed := check()
+	scope.Declare("typed", &typed)
+	godebug.Line(ctx, scope, 26)
+	var ptr *myErr
+	scope.Declare("ptr", &ptr)
+	godebug.Line(ctx, scope, 27)
+	h := holder{err: typed}
+	scope.Declare("h", &h)
+	godebug.Line(ctx, scope, 28)
+	var any interface{} = ptr
+	scope.Declare("any", &any)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 29)
+	godebug.Line(ctx, scope, 30)
+
+	println(none == nil, typed == nil, ptr == nil, h.err == nil, any == nil)
+}
+
+var typednil_in_go_contents = `package main
+
+type myErr struct{ msg string }
+
+func (e *myErr) Error() string { return e.msg }
+
+type holder struct {
+	err error
+	ptr *myErr
+}
+
+func find(fail bool) *myErr {
+	if fail {
+		return &myErr{"failed"}
+	}
+	return nil
+}
+
+func check() error {
+	return find(false)
+}
+
+func main() {
+	var none error
+	typed := check()
+	var ptr *myErr
+	h := holder{err: typed}
+	var any interface{} = ptr
+	_ = "breakpoint"
+	println(none == nil, typed == nil, ptr == nil, h.err == nil, any == nil)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"find": find,
+		"check": check,
+		"main": main,
+	}
+}
\ No newline at end of file
@@ -2,7 +2,7 @@ package main
 
 import "github.com/mailgun/godebug/lib"
 
-var unnamed_input_in_go_scope = godebug.EnteringNewFile(main_pkg_scope, unnamed_input_in_go_contents)
+var unnamed_input_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/unnamed_input-in.go", unnamed_input_in_go_contents)
 
 func main() {
 	ctx, ok := godebug.EnterFunc(main)
@@ -13,18 +13,16 @@ func main() {
 	foo(3, 3)
 }
 
-func foo(int, int) (string, error) {
+func foo(int, int) (result1 string, result2 error) {
 	var input1 int
 	var input2 int
-	var result1 string
-	var result2 error
 	ctx, ok := godebug.EnterFunc(func() {
 		result1, result2 = foo(input1, input2)
 	})
 	if !ok {
 		return result1, result2
 	}
-	defer godebug.ExitFunc(ctx)
+	defer godebug.ExitFunc(ctx, &result1, &result2)
 	godebug.Line(ctx, unnamed_input_in_go_scope, 8)
 	return "hello", nil
 }
@@ -0,0 +1,24 @@
+package main
+
+func sum(n int) int {
+	total := 0
+	for i := 1; i <= n; i++ {
+		total += i
+	}
+	return total
+}
+
+func countdown(n int) {
+	if n == 0 {
+		return
+	}
+	countdown(n - 1)
+	println(n)
+}
+
+func main() {
+	_ = "breakpoint"
+	s := sum(4)
+	countdown(2)
+	println(s)
+}
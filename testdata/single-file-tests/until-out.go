@@ -0,0 +1,113 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var until_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/until-in.go", until_in_go_contents)
+
+func sum(n int) (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = sum(n)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := until_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 4)
+	total := 0
+	scope.Declare("total", &total)
+	{
+		scope := scope.EnteringNewChildScope()
+		for i := 1; i <= n; i++ {
+			godebug.Line(ctx, scope, 5)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 6)
+			total += i
+			godebug.Assigned(ctx, scope, 6)
+		}
+		godebug.Line(ctx, scope, 5)
+	}
+	godebug.Line(ctx, scope, 8)
+	return total
+}
+
+func countdown(n int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		countdown(n)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := until_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 12)
+	if n == 0 {
+		godebug.Line(ctx, scope, 13)
+		return
+	}
+	godebug.Line(ctx, scope, 15)
+	countdown(n - 1)
+	godebug.Line(ctx, scope, 16)
+	println(n)
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, until_in_go_scope, 20)
+	godebug.Line(ctx, until_in_go_scope, 21)
+
+	s := sum(4)
+	scope := until_in_go_scope.EnteringNewChildScope()
+	scope.Declare("s", &s)
+	godebug.Line(ctx, scope, 22)
+	countdown(2)
+	godebug.Line(ctx, scope, 23)
+	println(s)
+}
+
+var until_in_go_contents = `package main
+
+func sum(n int) int {
+	total := 0
+	for i := 1; i <= n; i++ {
+		total += i
+	}
+	return total
+}
+
+func countdown(n int) {
+	if n == 0 {
+		return
+	}
+	countdown(n - 1)
+	println(n)
+}
+
+func main() {
+	_ = "breakpoint"
+	s := sum(4)
+	countdown(2)
+	println(s)
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"sum": sum,
+		"countdown": countdown,
+		"main": main,
+	}
+}
\ No newline at end of file
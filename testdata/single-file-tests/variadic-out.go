@@ -2,17 +2,16 @@ package main
 
 import "github.com/mailgun/godebug/lib"
 
-var variadic_in_go_scope = godebug.EnteringNewFile(main_pkg_scope, variadic_in_go_contents)
+var variadic_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/variadic-in.go", variadic_in_go_contents)
 
-func Varargs(i ...int) int {
-	var result1 int
+func Varargs(i ...int) (result1 int) {
 	ctx, ok := godebug.EnterFunc(func() {
 		result1 = Varargs(i...)
 	})
 	if !ok {
 		return result1
 	}
-	defer godebug.ExitFunc(ctx)
+	defer godebug.ExitFunc(ctx, &result1)
 	scope := variadic_in_go_scope.EnteringNewChildScope()
 	scope.Declare("i", &i)
 	godebug.Line(ctx, scope, 4)
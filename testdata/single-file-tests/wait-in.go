@@ -0,0 +1,18 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+func worker(done chan bool) {
+	godebug.LabelGoroutine("worker")
+	_ = "breakpoint"
+	done <- true
+	select {}
+}
+
+func main() {
+	godebug.LabelGoroutine("main")
+	done := make(chan bool)
+	go worker(done)
+	<-done
+	println("main is done")
+}
@@ -0,0 +1,82 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var wait_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/wait-in.go", wait_in_go_contents)
+
+func worker(done chan bool) {
+	ctx, ok := godebug.EnterFunc(func() {
+		worker(done)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := wait_in_go_scope.EnteringNewChildScope()
+	scope.Declare("done", &done)
+	godebug.Line(ctx, scope, 6)
+	godebug.LabelGoroutine("worker")
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 7)
+	godebug.Line(ctx, scope, 8)
+
+	done <- true
+	godebug.Select(ctx, scope, 9)
+	select {
+	case <-godebug.EndSelect(ctx, scope):
+		panic("impossible")
+	}
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, wait_in_go_scope, 13)
+	godebug.LabelGoroutine("main")
+	godebug.Line(ctx, wait_in_go_scope, 14)
+	done := make(chan bool)
+	scope := wait_in_go_scope.EnteringNewChildScope()
+	scope.Declare("done", &done)
+	godebug.Line(ctx, scope, 15)
+	go worker(done)
+	godebug.Line(ctx, scope, 16)
+	<-done
+	godebug.Line(ctx, scope, 17)
+	println("main is done")
+}
+
+var wait_in_go_contents = `package main
+
+import "github.com/mailgun/godebug/lib"
+
+func worker(done chan bool) {
+	godebug.LabelGoroutine("worker")
+	_ = "breakpoint"
+	done <- true
+	select {}
+}
+
+func main() {
+	godebug.LabelGoroutine("main")
+	done := make(chan bool)
+	go worker(done)
+	<-done
+	println("main is done")
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"worker": worker,
+		"main": main,
+	}
+}
\ No newline at end of file
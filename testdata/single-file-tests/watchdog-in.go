@@ -0,0 +1,13 @@
+package main
+
+func worker() {
+	n := 0
+	for { n++ }
+}
+
+func main() {
+	block := make(chan bool)
+	go worker()
+	_ = "breakpoint"
+	<-block
+}
@@ -0,0 +1,71 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var watchdog_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/watchdog-in.go", watchdog_in_go_contents)
+
+func worker() {
+	ctx, ok := godebug.EnterFunc(worker)
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	godebug.Line(ctx, watchdog_in_go_scope, 4)
+	n := 0
+	scope := watchdog_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.Line(ctx, scope, 5)
+	for {
+		godebug.Line(ctx, scope, 5)
+		n++
+		godebug.Assigned(ctx, scope, 5)
+		godebug.Line(ctx, scope, 5)
+	}
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, watchdog_in_go_scope, 9)
+	block := make(chan bool)
+	scope := watchdog_in_go_scope.EnteringNewChildScope()
+	scope.Declare("block", &block)
+	godebug.Line(ctx, scope, 10)
+	go worker()
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 11)
+	godebug.Line(ctx, scope, 12)
+
+	<-block
+}
+
+var watchdog_in_go_contents = `package main
+
+func worker() {
+	n := 0
+	for { n++ }
+}
+
+func main() {
+	block := make(chan bool)
+	go worker()
+	_ = "breakpoint"
+	<-block
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"worker": worker,
+		"main": main,
+	}
+}
\ No newline at end of file
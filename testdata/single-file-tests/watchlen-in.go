@@ -0,0 +1,23 @@
+package main
+
+func fill(m map[int]bool, n int) {
+	for i := 0; i < n; i++ {
+		if i%2 == 0 {
+			m[i] = true
+		}
+	}
+}
+
+func main() {
+	var s []int
+	m := make(map[int]bool)
+	x := 0
+	_ = "breakpoint"
+	for i := 0; i < 3; i++ {
+		x += i
+	}
+	s = append(s, x)
+	s[0] = 7
+	fill(m, 3)
+	_, _ = s, m
+}
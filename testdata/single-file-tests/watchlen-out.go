@@ -0,0 +1,111 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var watchlen_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "testdata/single-file-tests/watchlen-in.go", watchlen_in_go_contents)
+
+func fill(m map[int]bool, n int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		fill(m, n)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := watchlen_in_go_scope.EnteringNewChildScope()
+	scope.Declare("m", &m, "n", &n)
+	{
+		scope := scope.EnteringNewChildScope()
+		for i := 0; i < n; i++ {
+			godebug.Line(ctx, scope, 4)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 5)
+			if i%2 == 0 {
+				godebug.Line(ctx, scope, 6)
+				m[i] = true
+				godebug.Assigned(ctx, scope, 6)
+			}
+		}
+		godebug.Line(ctx, scope, 4)
+	}
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, watchlen_in_go_scope, 12)
+	var s []int
+	scope := watchlen_in_go_scope.EnteringNewChildScope()
+	scope.Declare("s", &s)
+	godebug.Line(ctx, scope, 13)
+	m := make(map[int]bool)
+	scope.Declare("m", &m)
+	godebug.Line(ctx, scope, 14)
+	x := 0
+	scope.Declare("x", &x)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 15)
+	{
+		scope := scope.EnteringNewChildScope()
+
+		for i := 0; i < 3; i++ {
+			godebug.Line(ctx, scope, 16)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 17)
+			x += i
+			godebug.Assigned(ctx, scope, 17)
+		}
+		godebug.Line(ctx, scope, 16)
+	}
+	godebug.Line(ctx, scope, 19)
+	s = append(s, x)
+	godebug.Assigned(ctx, scope, 19)
+	godebug.Line(ctx, scope, 20)
+	s[0] = 7
+	godebug.Assigned(ctx, scope, 20)
+	godebug.Line(ctx, scope, 21)
+	fill(m, 3)
+	godebug.Line(ctx, scope, 22)
+	_, _ = s, m
+}
+
+var watchlen_in_go_contents = `package main
+
+func fill(m map[int]bool, n int) {
+	for i := 0; i < n; i++ {
+		if i%2 == 0 {
+			m[i] = true
+		}
+	}
+}
+
+func main() {
+	var s []int
+	m := make(map[int]bool)
+	x := 0
+	_ = "breakpoint"
+	for i := 0; i < 3; i++ {
+		x += i
+	}
+	s = append(s, x)
+	s[0] = 7
+	fill(m, 3)
+	_, _ = s, m
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"fill": fill,
+		"main": main,
+	}
+}
\ No newline at end of file
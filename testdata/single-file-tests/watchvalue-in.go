@@ -0,0 +1,31 @@
+package main
+
+type point struct {
+	x, y int
+}
+
+func main() {
+	total := 0
+	p := point{1, 2}
+	scores := []int{0, 0, 0}
+	_ = "breakpoint"
+	for i := 1; i <= 3; i++ {
+		scores[i-1] = i * 10
+		if i == 2 {
+			p.y = 5
+		}
+		bump(&total, i)
+	}
+	println(total, p.x, p.y, len(scores))
+	println(half(total))
+}
+
+func bump(n *int, by int) {
+	*n += by
+}
+
+func half(n int) int {
+	_ = "breakpoint"
+	n /= 2
+	return n
+}
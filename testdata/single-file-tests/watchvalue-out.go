@@ -0,0 +1,135 @@
+package main
+
+import "github.com/mailgun/godebug/lib"
+
+var watchvalue_in_go_scope = godebug.EnteringNewFileAt(main_pkg_scope, "watchvalue-in.go", watchvalue_in_go_contents)
+
+type point struct {
+	x, y int
+}
+
+func main() {
+	ctx, ok := godebug.EnterFunc(main)
+	if !ok {
+		return
+	}
+	godebug.Line(ctx, watchvalue_in_go_scope, 8)
+	total := 0
+	scope := watchvalue_in_go_scope.EnteringNewChildScope()
+	scope.Declare("total", &total)
+	godebug.Line(ctx, scope, 9)
+	p := point{1, 2}
+	scope.Declare("p", &p)
+	godebug.Line(ctx, scope, 10)
+	scores := []int{0, 0, 0}
+	scope.Declare("scores", &scores)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 11)
+	{
+		scope := scope.EnteringNewChildScope()
+
+		for i := 1; i <= 3; i++ {
+			godebug.Line(ctx, scope, 12)
+			scope.Declare("i", &i)
+			godebug.Line(ctx, scope, 13)
+			scores[i-1] = i * 10
+			godebug.Assigned(ctx, scope, 13)
+			godebug.Line(ctx, scope, 14)
+			if i == 2 {
+				godebug.Line(ctx, scope, 15)
+				p.y = 5
+				godebug.Assigned(ctx, scope, 15)
+			}
+			godebug.Line(ctx, scope, 17)
+			bump(&total, i)
+		}
+		godebug.Line(ctx, scope, 12)
+	}
+	godebug.Line(ctx, scope, 19)
+	println(total, p.x, p.y, len(scores))
+	godebug.Line(ctx, scope, 20)
+	println(half(total))
+}
+
+func bump(n *int, by int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		bump(n, by)
+	})
+	if !ok {
+		return
+	}
+	defer godebug.ExitFunc(ctx)
+	scope := watchvalue_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n, "by", &by)
+	godebug.Line(ctx, scope, 24)
+	*n += by
+	godebug.Assigned(ctx, scope, 24)
+}
+
+func half(n int) (result1 int) {
+	ctx, ok := godebug.EnterFunc(func() {
+		result1 = half(n)
+	})
+	if !ok {
+		return result1
+	}
+	defer godebug.ExitFunc(ctx, &result1)
+	scope := watchvalue_in_go_scope.EnteringNewChildScope()
+	scope.Declare("n", &n)
+	godebug.SetTraceGen(ctx)
+	godebug.Line(ctx, scope, 28)
+	godebug.Line(ctx, scope, 29)
+
+	n /= 2
+	godebug.Assigned(ctx, scope, 29)
+	godebug.Line(ctx, scope, 30)
+	return n
+}
+
+var watchvalue_in_go_contents = `package main
+
+type point struct {
+	x, y int
+}
+
+func main() {
+	total := 0
+	p := point{1, 2}
+	scores := []int{0, 0, 0}
+	_ = "breakpoint"
+	for i := 1; i <= 3; i++ {
+		scores[i-1] = i * 10
+		if i == 2 {
+			p.y = 5
+		}
+		bump(&total, i)
+	}
+	println(total, p.x, p.y, len(scores))
+	println(half(total))
+}
+
+func bump(n *int, by int) {
+	*n += by
+}
+
+func half(n int) int {
+	_ = "breakpoint"
+	n /= 2
+	return n
+}
+`
+
+
+var main_pkg_scope = &godebug.Scope{}
+
+func init() {
+	main_pkg_scope.Vars = map[string]interface{}{
+	}
+	main_pkg_scope.Consts = map[string]interface{}{
+	}
+	main_pkg_scope.Funcs = map[string]interface{}{
+		"main": main,
+		"bump": bump,
+		"half": half,
+	}
+}
\ No newline at end of file